@@ -0,0 +1,72 @@
+package businesshours
+
+import (
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// 営業日・営業時間・祝日の定義
+type Calendar struct {
+	// 営業時間を解釈するタイムゾーン
+	Location *time.Location
+	// 営業日の曜日
+	Weekdays []time.Weekday
+	// 営業開始・終了の時刻。0 時からの経過時間で表す
+	Start time.Duration
+	End   time.Duration
+	// 休業日。2006-01-02 形式
+	Holidays map[string]bool
+}
+
+// 月曜 ~ 金曜の 9:00 ~ 18:00
+func Default(loc *time.Location) *Calendar {
+	return &Calendar{
+		Location: loc,
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Start:    9 * time.Hour,
+		End:      18 * time.Hour,
+		Holidays: map[string]bool{},
+	}
+}
+
+// date の日が営業日かどうか
+func (c *Calendar) IsWorkingDay(date time.Time) bool {
+	date = date.In(c.Location)
+	return lo.Contains(c.Weekdays, date.Weekday()) && !c.Holidays[date.Format("2006-01-02")]
+}
+
+// from から to までのうち、営業時間に含まれる時間を返す。to が from より前の場合は 0
+func (c *Calendar) Duration(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	from, to = from.In(c.Location), to.In(c.Location)
+
+	var total time.Duration
+	for day := truncateToDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !c.IsWorkingDay(day) {
+			continue
+		}
+
+		// 夏時間の切り替えがあっても時計の時刻で区切るため、日付から組み立て直す
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.Location).Add(c.Start)
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.Location).Add(c.End)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+
+	return total
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
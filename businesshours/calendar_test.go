@@ -0,0 +1,41 @@
+package businesshours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_Duration(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	calendar := Default(tokyo)
+	calendar.Holidays["2023-05-03"] = true
+
+	// 2023-05-01 は月曜
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2023, time.May, day, hour, minute, 0, 0, tokyo)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{name: "within a working day", from: at(1, 10, 0), to: at(1, 12, 30), want: 2*time.Hour + 30*time.Minute},
+		{name: "clipped to business hours", from: at(1, 7, 0), to: at(1, 20, 0), want: 9 * time.Hour},
+		{name: "after hours", from: at(1, 19, 0), to: at(2, 8, 0), want: 0},
+		{name: "across days", from: at(1, 17, 0), to: at(2, 10, 0), want: 2 * time.Hour},
+		{name: "skips holidays", from: at(2, 17, 0), to: at(4, 10, 0), want: 2 * time.Hour},
+		{name: "skips weekends", from: at(5, 17, 0), to: at(8, 10, 0), want: 2 * time.Hour},
+		{name: "whole week", from: at(1, 0, 0), to: at(8, 0, 0), want: 4 * 9 * time.Hour},
+		{name: "interpreted in the calendar time zone", from: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC), to: time.Date(2023, time.May, 1, 3, 0, 0, 0, time.UTC), want: 3 * time.Hour},
+		{name: "empty", from: at(1, 10, 0), to: at(1, 10, 0), want: 0},
+		{name: "reversed", from: at(1, 12, 0), to: at(1, 10, 0), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.Duration(tt.from, tt.to); got != tt.want {
+				t.Errorf("Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
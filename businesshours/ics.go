@@ -0,0 +1,115 @@
+package businesshours
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// iCalendar (RFC 5545) の VEVENT を休業日として読み込み、2006-01-02 形式の日付を返す
+// 終日の予定だけでなく時刻つきの予定も、その予定がかかる日をすべて休業日とみなす
+func ParseICS(r io.Reader) ([]string, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []string
+	var inEvent bool
+	var start, end time.Time
+	var endIsDate bool
+	for i, line := range lines {
+		name, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end, endIsDate = time.Time{}, time.Time{}, false
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			dates = append(dates, eventDates(start, end, endIsDate)...)
+		case inEvent && name == "DTSTART":
+			if start, _, err = parseDateTime(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTSTART: %w", i+1, err)
+			}
+		case inEvent && name == "DTEND":
+			if end, endIsDate, err = parseDateTime(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTEND: %w", i+1, err)
+			}
+		}
+	}
+
+	return dates, nil
+}
+
+// 75 文字で折り返された行 (次の行が空白で始まる) を 1 行に戻す
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ics: %w", err)
+	}
+
+	return lines, nil
+}
+
+// DTSTART;VALUE=DATE:20230101 のような行をプロパティ名と値に分ける。パラメータは捨てる
+func splitProperty(line string) (string, string, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+
+	name, _, _ := strings.Cut(head, ";")
+	return strings.ToUpper(name), value, true
+}
+
+// 日付のみの値の場合は true を返す。TZID は無視してその日付をそのまま使う
+func parseDateTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, false, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("unsupported date %q", value)
+}
+
+// 終日の予定の DTEND はその日を含まない
+func eventDates(start, end time.Time, endIsDate bool) []string {
+	start = truncateToDay(start)
+	last := start
+	if !end.IsZero() {
+		last = truncateToDay(end)
+		if endIsDate || end.Equal(last) {
+			last = last.AddDate(0, 0, -1)
+		}
+	}
+
+	dates := []string{start.Format("2006-01-02")}
+	for day := start.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day.Format("2006-01-02"))
+	}
+
+	return dates
+}
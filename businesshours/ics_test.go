@@ -0,0 +1,81 @@
+package businesshours
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseICS(t *testing.T) {
+	tests := []struct {
+		name    string
+		ics     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "all-day events exclude DTEND",
+			ics: strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20230101",
+				"DTEND;VALUE=DATE:20230102",
+				"SUMMARY:New Year's Day",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20230503",
+				"DTEND;VALUE=DATE:20230506",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			}, "\r\n"),
+			want: []string{"2023-01-01", "2023-05-03", "2023-05-04", "2023-05-05"},
+		},
+		{
+			name: "all-day event without DTEND",
+			ics:  "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20230711\nEND:VEVENT\n",
+			want: []string{"2023-07-11"},
+		},
+		{
+			name: "timed event covers every day it touches",
+			ics:  "BEGIN:VEVENT\nDTSTART;TZID=Asia/Tokyo:20230810T220000\nDTEND;TZID=Asia/Tokyo:20230811T010000\nEND:VEVENT\n",
+			want: []string{"2023-08-10", "2023-08-11"},
+		},
+		{
+			name: "timed event ending at midnight",
+			ics:  "BEGIN:VEVENT\nDTSTART:20230810T090000Z\nDTEND:20230811T000000Z\nEND:VEVENT\n",
+			want: []string{"2023-08-10"},
+		},
+		{
+			name: "folded lines",
+			ics:  "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2023\n 1225\nSUMMARY:Christ\n mas\nEND:VEVENT\n",
+			want: []string{"2023-12-25"},
+		},
+		{
+			name:    "event without DTSTART",
+			ics:     "BEGIN:VEVENT\nSUMMARY:Nothing\nEND:VEVENT\n",
+			wantErr: "line 3: event without DTSTART",
+		},
+		{
+			name:    "invalid date",
+			ics:     "BEGIN:VEVENT\nDTSTART:2023-01-01\nEND:VEVENT\n",
+			wantErr: `line 2: invalid DTSTART: unsupported date "2023-01-01"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseICS(strings.NewReader(tt.ics))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseICS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseICS() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseICS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runAll(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Wrap every section in the year.")
+	flags.RegisterFilterFlags()
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := wrapper.Wrap(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, result)
+}
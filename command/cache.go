@@ -0,0 +1,49 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func runCache(name string, args []string) error {
+	fs, flags := newFlagSet(name, "<path|list|clear> [flags]", "Show or clear the API response cache.")
+
+	if _, err := parse(fs, flags, args); err != nil {
+		return err
+	}
+
+	dir, err := config.DefaultCacheDir()
+	if err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "path":
+		fmt.Println(dir)
+	case "list":
+		entries, err := repository.ListCacheEntries(dir)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, entry := range entries {
+			fmt.Fprintf(tw, "%s\t%d bytes\t%s\n", entry.Path, entry.Size, entry.UpdatedAt.Format("2006-01-02 15:04:05"))
+		}
+		tw.Flush()
+	case "clear":
+		if err := repository.ClearCache(dir); err != nil {
+			return err
+		}
+		fmt.Println("cleared", dir)
+	default:
+		fs.Usage()
+		return fmt.Errorf("%w: unknown cache command %q", ErrUsage, fs.Arg(0))
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runCalendar(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Show the contribution calendar of the year as a heatmap, with streaks.")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	calendar, err := wrapper.WrapCalendar(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap contribution calendar: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, &wrapper.WrappedResult{
+		Login:    calendar.Login,
+		Year:     cfg.Year(),
+		Calendar: calendar,
+	})
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+// API のレスポンスをキャッシュしておく時間
+const cacheTTL = 12 * time.Hour
+
+// フラグの指定ミスなど、使い方が間違っている場合のエラー
+var ErrUsage = errors.New("usage error")
+
+type command struct {
+	name    string
+	summary string
+	run     func(name string, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	// init で組み立てないと help から commands を参照したときに初期化の循環になる
+	commands = []*command{
+		{name: "prs", summary: "Wrap pull requests you opened", run: runPullRequests},
+		{name: "reviews", summary: "Wrap reviews you gave", run: runReviews},
+		{name: "issues", summary: "Wrap issues you opened", run: runIssues},
+		{name: "commits", summary: "Wrap commits you made", run: runCommits},
+		{name: "rhythm", summary: "Show the hours and weekdays you were active", run: runRhythm},
+		{name: "graph", summary: "Export the collaboration graph as Graphviz DOT or GraphML", run: runGraph},
+		{name: "calendar", summary: "Show the contribution calendar and streaks", run: runCalendar},
+		{name: "all", summary: "Wrap every section", run: runAll},
+		{name: "export", summary: "Write the wrapped result of every section to a file", run: runExport},
+		{name: "doctor", summary: "Check authentication, config and API access", run: runDoctor},
+		{name: "cache", summary: "Show or clear the API response cache", run: runCache},
+		{name: "serve", summary: "Serve the wrapped result as a web page", run: runServe},
+		{name: "help", summary: "Show help for a command", run: runHelp},
+	}
+}
+
+// args はサブコマンド名から始まる引数。サブコマンドを省略した場合は prs として扱う
+func Run(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runPullRequests("prs", args)
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(c.name, args[1:])
+		}
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gh wrapped <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "gh wrapped <command> -h" for the flags of each command.`)
+}
+
+func runHelp(_ string, args []string) error {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] && c.name != "help" {
+			return c.run(c.name, []string{"-h"})
+		}
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+}
+
+// サブコマンド用の FlagSet を作り、共通のフラグを登録する
+func newFlagSet(name, usage, description string) (*flag.FlagSet, *config.Flags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gh wrapped %s %s\n\n%s\n\nFlags:\n", name, usage, description)
+		fs.PrintDefaults()
+	}
+
+	return fs, config.RegisterFlags(fs)
+}
+
+// フラグをパースして設定を読み込み、ロガーを設定する
+func parse(fs *flag.FlagSet, flags *config.Flags, args []string) (*config.Config, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+
+	cfg, err := flags.Load(wrapper.AchievementValidator())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+
+	setupLogger(cfg)
+
+	return cfg, nil
+}
+
+// GitHub のクライアントを作る。--no-cache でない限りレスポンスをキャッシュする
+func newRepository(cfg *config.Config) (repository.GitHubRepository, error) {
+	client, err := repository.NewGitHub(cfg.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	if cfg.NoCache {
+		return client, nil
+	}
+
+	cacheDir, err := config.DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.NewCachedGitHub(
+		client,
+		repository.CacheDirFor(cacheDir, client.Host()),
+		cacheTTL,
+	), nil
+}
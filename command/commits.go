@@ -0,0 +1,34 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runCommits(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Wrap the commits you made in the year, grouped by repository.")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	commits, err := wrapper.WrapCommits(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap commits: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, &wrapper.WrappedResult{
+		Login:   commits.Login,
+		Year:    cfg.Year(),
+		Commits: commits,
+	})
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/filter"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+	"github.com/samber/lo"
+)
+
+func runDoctor(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Check authentication, config and API access.")
+	flags.RegisterFilterFlags()
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	check := func(title string, fn func() (string, error)) {
+		detail, err := fn()
+		if err != nil {
+			failed = true
+			fmt.Printf("✗ %s: %v\n", title, err)
+			return
+		}
+		fmt.Printf("✓ %s: %s\n", title, detail)
+	}
+
+	check("config", func() (string, error) {
+		if cfg.FilePath == "" {
+			return "no config file, using defaults", nil
+		}
+		return cfg.FilePath + lo.Ternary(cfg.Profile != "", " (profile: "+cfg.Profile+")", ""), nil
+	})
+
+	check("filter", func() (string, error) {
+		if _, err := filter.New(cfg.Filter); err != nil {
+			return "", err
+		}
+		return "ok", nil
+	})
+
+	check("metrics", func() (string, error) {
+		enabled, err := wrapper.EnabledPullRequestMetrics(cfg)
+		if err != nil {
+			return "", err
+		}
+
+		enabledNames := lo.Map(enabled, func(metric wrapper.Metric, _ int) string { return metric.Name() })
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d of %d pull request metrics enabled", len(enabled), len(wrapper.PullRequestMetrics()))
+		for _, metric := range wrapper.PullRequestMetrics() {
+			requires := lo.Map(metric.Requires(), func(data wrapper.MetricData, _ int) string { return string(data) })
+			fmt.Fprintf(
+				&b,
+				"\n    %s %s (requires %s)",
+				lo.Ternary(lo.Contains(enabledNames, metric.Name()), "+", "-"),
+				metric.Name(),
+				strings.Join(requires, ", "),
+			)
+		}
+		return b.String(), nil
+	})
+
+	host := cfg.Host
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
+	check("authentication", func() (string, error) {
+		if token, _ := auth.TokenForHost(host); token == "" {
+			return "", fmt.Errorf("no token for %s, run `gh auth login --hostname %s`", host, host)
+		}
+		return "token found for " + host, nil
+	})
+
+	client, err := repository.NewGitHub(cfg.Host)
+	if err != nil {
+		check("API", func() (string, error) {
+			return "", err
+		})
+	} else {
+		check("API", func() (string, error) {
+			user, err := client.GetMe()
+			if err != nil {
+				return "", err
+			}
+			return "logged in as " + user.Login, nil
+		})
+		check("rate limit", func() (string, error) {
+			rateLimit, err := client.GetRateLimit()
+			if err != nil {
+				return "", err
+			}
+			if rateLimit.Remaining == 0 {
+				return "", fmt.Errorf("exhausted, resets at %s", rateLimit.ResetAt.Format("15:04:05"))
+			}
+			return fmt.Sprintf("%d/%d GraphQL points remaining", rateLimit.Remaining, rateLimit.Limit), nil
+		})
+	}
+
+	check("cache", func() (string, error) {
+		if cfg.NoCache {
+			return "disabled", nil
+		}
+
+		dir, err := config.DefaultCacheDir()
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return "", err
+		}
+
+		f, err := os.CreateTemp(dir, "doctor-*")
+		if err != nil {
+			return "", fmt.Errorf("%s is not writable: %w", dir, err)
+		}
+		f.Close()
+		os.Remove(f.Name())
+
+		return dir, nil
+	})
+
+	if failed {
+		return errors.New("some checks failed")
+	}
+
+	return nil
+}
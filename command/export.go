@@ -0,0 +1,73 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+	"github.com/samber/lo"
+)
+
+func runExport(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", strings.Join([]string{
+		"Wrap every section in the year and write it to a file.",
+		"The format is inferred from the extension of --output unless --format is given.",
+	}, "\n"))
+	flags.RegisterFilterFlags()
+	var output string
+	fs.StringVar(&output, "output", "", "path of the file to write (default: wrapped-<year>.<format>)")
+	fs.StringVar(&output, "o", "", "shorthand for --output")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	format := cfg.Format
+	if !flags.IsSet("format") {
+		// text だとファイルに書き出す意味が薄いので json をデフォルトにする
+		format = "json"
+		if ext := strings.TrimPrefix(filepath.Ext(output), "."); lo.Contains(config.Formats, ext) {
+			format = ext
+		} else if ext == "txt" {
+			format = "text"
+		}
+	}
+
+	if output == "" {
+		output = "wrapped-" + cfg.YearString() + render.Extension(format)
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := wrapper.Wrap(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap: %w", err)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if err := render.Render(f, format, result); err != nil {
+		return errors.Join(err, f.Close())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	slog.Info("exported", "path", output, "format", format)
+
+	return nil
+}
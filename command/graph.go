@@ -0,0 +1,72 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+	"github.com/samber/lo"
+)
+
+func runGraph(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", strings.Join([]string{
+		"Export the graph of who reviewed whom and who replied to whom in the year.",
+		"The graph format is inferred from the extension of --output unless --graph-format is given.",
+	}, "\n"))
+	flags.RegisterFilterFlags()
+	var output, graphFormat string
+	fs.StringVar(&output, "output", "", "path of the file to write (default: stdout)")
+	fs.StringVar(&output, "o", "", "shorthand for --output")
+	fs.StringVar(&graphFormat, "graph-format", "", "graph format: "+strings.Join(render.GraphFormats, ", ")+" (default: dot)")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	if graphFormat == "" {
+		graphFormat = "dot"
+		if ext := strings.TrimPrefix(filepath.Ext(output), "."); lo.Contains(render.GraphFormats, ext) {
+			graphFormat = ext
+		}
+	}
+	if !lo.Contains(render.GraphFormats, graphFormat) {
+		return fmt.Errorf("%w: --graph-format: must be one of %s", ErrUsage, strings.Join(render.GraphFormats, ", "))
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	graph, err := wrapper.WrapCollaborationGraph(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap collaboration: %w", err)
+	}
+
+	if output == "" {
+		return render.RenderGraph(os.Stdout, graphFormat, graph)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if err := render.RenderGraph(f, graphFormat, graph); err != nil {
+		return errors.Join(err, f.Close())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	slog.Info("exported", "path", output, "format", graphFormat)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runIssues(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Wrap the issues you opened in the year.")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	issues, err := wrapper.WrapIssues(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap issues: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, &wrapper.WrappedResult{
+		Login:  issues.Login,
+		Year:   cfg.Year(),
+		Issues: issues,
+	})
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"log/slog"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/m-mizutani/clog"
+	"github.com/samber/lo"
+)
+
+func setupLogger(cfg *config.Config) {
+	logger := slog.New(
+		clog.New(
+			clog.WithSource(cfg.DebugMode),
+			clog.WithLevel(
+				lo.Ternary(
+					cfg.DebugMode,
+					slog.LevelDebug,
+					slog.LevelInfo,
+				),
+			),
+		),
+	)
+
+	slog.SetDefault(logger)
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runReviews(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Wrap the reviews you gave in the year.")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := wrapper.WrapReviews(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap reviews: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, &wrapper.WrappedResult{
+		Login:   reviews.Login,
+		Year:    cfg.Year(),
+		Reviews: reviews,
+	})
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runRhythm(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Show the hours and weekdays you opened pull requests, got them merged and reviewed, in --timezone.")
+	flags.RegisterFilterFlags()
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	rhythm, err := wrapper.WrapRhythm(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap activity rhythm: %w", err)
+	}
+
+	return render.Render(os.Stdout, cfg.Format, &wrapper.WrappedResult{
+		Login:  rhythm.Login,
+		Year:   cfg.Year(),
+		Rhythm: rhythm,
+	})
+}
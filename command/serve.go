@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/render"
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+func runServe(name string, args []string) error {
+	fs, flags := newFlagSet(name, "[flags]", "Wrap every section in the year and serve it as a web page.\nThe result is computed once at startup.")
+	flags.RegisterFilterFlags()
+	var addr string
+	fs.StringVar(&addr, "addr", "127.0.0.1:8080", "address to listen on")
+
+	cfg, err := parse(fs, flags, args)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := wrapper.Wrap(repo, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to wrap: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	for path, format := range map[string]string{
+		"/":             "html",
+		"/wrapped.json": "json",
+		"/card.svg":     "svg",
+	} {
+		mux.Handle(path, renderHandler(result, format))
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	slog.Info("serving", "url", "http://"+addr)
+
+	return server.ListenAndServe()
+}
+
+func renderHandler(result *wrapper.WrappedResult, format string) http.Handler {
+	contentType := map[string]string{
+		"html": "text/html; charset=utf-8",
+		"json": "application/json",
+		"svg":  "image/svg+xml",
+	}[format]
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// "/" はすべてのパスにマッチするので、それ以外は 404 にする
+		if format == "html" && r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := render.Render(&buf, format, result); err != nil {
+			slog.Error("failed to render", "format", format, "error", err)
+			http.Error(w, "failed to render", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		if _, err := buf.WriteTo(w); err != nil {
+			slog.Warn("failed to write response", "error", err)
+		}
+	})
+}
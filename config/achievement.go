@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// 設定ファイルの achievements で追加する実績。metric の値を threshold と比べて獲得したかを決める
+//
+//	achievements:
+//	  - name: Gatekeeper
+//	    description: Gave 200 reviews
+//	    metric: Reviews.TotalCount
+//	    operator: ">="
+//	    threshold: 200
+//
+// プロファイルで指定した場合はトップレベルの achievements を丸ごと置き換える
+type AchievementRule struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// PullRequests.MergedCount のような集計結果のフィールドのパス。duration のフィールドは時間単位で比べる
+	Metric string `yaml:"metric"`
+	// AchievementOperators のいずれか。未指定の場合は >=
+	Operator  string  `yaml:"operator"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+var AchievementOperators = []string{">=", ">", "<=", "<", "=="}
+
+// 集計結果の型と組み込みの実績は wrapper にあるので、設定ファイルの実績をそれらと照らし合わせる方法を Load に渡す
+type AchievementValidator struct {
+	// 組み込みの実績の名前。同じ名前の実績は追加できない
+	ReservedNames []string
+	// metric のパスが集計結果の数値のフィールドを指しているか確かめる。nil の場合は確かめない
+	ValidateMetric func(metric string) error
+}
+
+func validateAchievementRules(rules []AchievementRule, prefix []string, validator AchievementValidator) error {
+	key := func(i int, k string) []string {
+		return append(append([]string{}, prefix...), strconv.Itoa(i), k)
+	}
+
+	names := map[string]bool{}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return newKeyError(key(i, "name"), "must not be empty")
+		}
+		if names[rule.Name] {
+			return newKeyError(key(i, "name"), "duplicate achievement %q", rule.Name)
+		}
+		if lo.ContainsBy(validator.ReservedNames, func(name string) bool { return strings.EqualFold(name, rule.Name) }) {
+			return newKeyError(key(i, "name"), "%q is a built-in achievement", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if strings.TrimSpace(rule.Metric) == "" {
+			return newKeyError(key(i, "metric"), "must not be empty")
+		}
+		if validator.ValidateMetric != nil {
+			if err := validator.ValidateMetric(rule.Metric); err != nil {
+				return &keyError{key: key(i, "metric"), err: err}
+			}
+		}
+		if rule.Operator != "" && !lo.Contains(AchievementOperators, rule.Operator) {
+			return newKeyError(key(i, "operator"), "must be one of %s", strings.Join(AchievementOperators, ", "))
+		}
+	}
+
+	return nil
+}
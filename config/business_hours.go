@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/samber/lo"
+)
+
+// 設定ファイルの business_hours。指定した場合は経過時間を営業時間だけでも集計する
+//
+//	business_hours:
+//	  timezone: Asia/Tokyo
+//	  days: [mon, tue, wed, thu, fri]
+//	  start: "09:00"
+//	  end: "18:00"
+//	  holidays: ["2023-12-29"]
+//	  holidays_file: holidays.ics
+//
+// プロファイルで指定した場合はトップレベルの business_hours を丸ごと置き換える
+type fileBusinessHours struct {
+	// 未指定の場合は timezone と同じ
+	Timezone *string `yaml:"timezone"`
+	// 未指定の場合は月曜 ~ 金曜
+	Days []string `yaml:"days"`
+	// 15:04 形式。未指定の場合は 09:00 ~ 18:00
+	Start *string `yaml:"start"`
+	End   *string `yaml:"end"`
+	// 2006-01-02 形式
+	Holidays []string `yaml:"holidays"`
+	// 休業日を VEVENT として並べた iCalendar ファイル。相対パスは設定ファイルのディレクトリから解決する
+	HolidaysFile *string `yaml:"holidays_file"`
+
+	// holidays_file から読み込んだ日付
+	holidaysFromFile []string
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// 15:04 形式の時刻を 0 時からの経過時間にする。24:00 も受け付ける
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("must be in HH:MM format")
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (b fileBusinessHours) validate(prefix []string) error {
+	key := func(k ...string) []string {
+		return append(append([]string{}, prefix...), k...)
+	}
+
+	if b.Timezone != nil {
+		if _, err := time.LoadLocation(*b.Timezone); err != nil {
+			return newKeyError(key("timezone"), "unknown time zone %q", *b.Timezone)
+		}
+	}
+
+	for i, day := range b.Days {
+		if _, ok := weekdayNames[strings.ToLower(day)]; !ok {
+			return newKeyError(key("days", strconv.Itoa(i)), "must be one of sun, mon, tue, wed, thu, fri, sat")
+		}
+	}
+
+	defaults := businesshours.Default(time.UTC)
+	start, end := defaults.Start, defaults.End
+	if b.Start != nil {
+		clock, err := parseClock(*b.Start)
+		if err != nil {
+			return newKeyError(key("start"), "%v", err)
+		}
+		start = clock
+	}
+	if b.End != nil {
+		clock, err := parseClock(*b.End)
+		if err != nil {
+			return newKeyError(key("end"), "%v", err)
+		}
+		end = clock
+	}
+	if start >= end {
+		return newKeyError(key("end"), "must be after start")
+	}
+
+	for i, holiday := range b.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return newKeyError(key("holidays", strconv.Itoa(i)), "must be in YYYY-MM-DD format")
+		}
+	}
+
+	return nil
+}
+
+// holidays_file を読み込む。baseDir は相対パスの基準にするディレクトリ
+func (b *fileBusinessHours) loadHolidaysFile(prefix []string, baseDir string) error {
+	if b.HolidaysFile == nil {
+		return nil
+	}
+
+	key := append(append([]string{}, prefix...), "holidays_file")
+
+	filePath := *b.HolidaysFile
+	if rest, ok := strings.CutPrefix(filePath, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		filePath = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(baseDir, filePath)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return newKeyError(key, "failed to open %s: %v", filePath, err)
+	}
+	defer f.Close()
+
+	dates, err := businesshours.ParseICS(f)
+	if err != nil {
+		return newKeyError(key, "failed to parse %s: %v", filePath, err)
+	}
+	b.holidaysFromFile = dates
+
+	return nil
+}
+
+// validate で検証済みの値から Calendar を組み立てる。timezone が未指定の場合 Location は nil
+func (b fileBusinessHours) calendar() *businesshours.Calendar {
+	calendar := businesshours.Default(nil)
+	if b.Timezone != nil {
+		calendar.Location = lo.Must(time.LoadLocation(*b.Timezone))
+	}
+	if b.Days != nil {
+		calendar.Weekdays = lo.Map(b.Days, func(day string, _ int) time.Weekday {
+			return weekdayNames[strings.ToLower(day)]
+		})
+	}
+	if b.Start != nil {
+		calendar.Start = lo.Must(parseClock(*b.Start))
+	}
+	if b.End != nil {
+		calendar.End = lo.Must(parseClock(*b.End))
+	}
+	for _, holiday := range append(append([]string{}, b.Holidays...), b.holidaysFromFile...) {
+		calendar.Holidays[holiday] = true
+	}
+
+	return calendar
+}
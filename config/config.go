@@ -2,25 +2,368 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/samber/lo"
+)
+
+// 出力形式
+var Formats = []string{"text", "json", "html", "svg"}
+
+// GitHub で PR が作れるようになった年
+const MinYear = 2008
+
+var pullRequestStates = []string{"OPEN", "CLOSED", "MERGED"}
+
+// top_sections で件数を上書きできるランキング
+const (
+	RankingShortLivePullRequests     = "short_live_pull_requests"
+	RankingLongLivePullRequests      = "long_live_pull_requests"
+	RankingMostCommentedPullRequests = "most_commented_pull_requests"
+	RankingMostCommittedPullRequests = "most_committed_pull_requests"
+	RankingMostReviewedRepositories  = "most_reviewed_repositories"
+	RankingMostReviewedAuthors       = "most_reviewed_authors"
+	RankingMostCommentedIssues       = "most_commented_issues"
+	RankingIssueRepositories         = "issue_repositories"
+	RankingCommitRepositories        = "commit_repositories"
+	RankingDirectPushRepositories    = "direct_push_repositories"
+	RankingLargestPullRequests       = "largest_pull_requests"
+	RankingSlowestFirstReview        = "slowest_first_review"
+	RankingSlowestApproval           = "slowest_approval"
+	RankingSlowestMergeAfterApproval = "slowest_merge_after_approval"
+	RankingMostCycledPullRequests    = "most_cycled_pull_requests"
+	RankingChangeRequesters          = "change_requesters"
+	RankingTopReviewers              = "top_reviewers"
+	RankingTopDiscussionPartners     = "top_discussion_partners"
+	RankingTopCollaborators          = "top_collaborators"
+	RankingMostDebatedPullRequests   = "most_debated_pull_requests"
+	RankingTopBots                   = "top_bots"
+	RankingTopMergers                = "top_mergers"
+	RankingOldestOpenPullRequests    = "oldest_open_pull_requests"
+	RankingLeastActivePullRequests   = "least_active_pull_requests"
+)
+
+var Rankings = []string{
+	RankingShortLivePullRequests,
+	RankingLongLivePullRequests,
+	RankingMostCommentedPullRequests,
+	RankingMostCommittedPullRequests,
+	RankingMostReviewedRepositories,
+	RankingMostReviewedAuthors,
+	RankingMostCommentedIssues,
+	RankingIssueRepositories,
+	RankingCommitRepositories,
+	RankingDirectPushRepositories,
+	RankingLargestPullRequests,
+	RankingSlowestFirstReview,
+	RankingSlowestApproval,
+	RankingSlowestMergeAfterApproval,
+	RankingMostCycledPullRequests,
+	RankingChangeRequesters,
+	RankingTopReviewers,
+	RankingTopDiscussionPartners,
+	RankingTopCollaborators,
+	RankingMostDebatedPullRequests,
+	RankingTopBots,
+	RankingTopMergers,
+	RankingOldestOpenPullRequests,
+	RankingLeastActivePullRequests,
+}
+
+// PR の生存期間 (マージまでの時間) を数え始める時点
+const (
+	// PR を作成した時点
+	LifetimeFromCreated = "created"
+	// 最初に Ready for review になった時点。draft で作成しなかった PR は作成した時点
+	LifetimeFromReady = "ready"
 )
 
+var LifetimeFroms = []string{LifetimeFromCreated, LifetimeFromReady}
+
 type Config struct {
 	DebugMode bool
-	year      int
+	// 読み込んだ設定ファイルのパス。読み込まなかった場合は空
+	FilePath string
+	// 選択されたプロファイル。選択されなかった場合は空
+	Profile string
+	// GitHub のホスト名。空の場合は gh の設定に従う
+	Host   string
+	Format string
+	// ランキングに表示する件数
+	Top int
+	// ランキングごとに上書きした件数
+	TopSections map[string]int
+	// PR の生存期間を数え始める時点。LifetimeFromCreated または LifetimeFromReady
+	LifetimeFrom string
+	// 営業時間の定義。nil の場合は営業時間での集計をしない
+	BusinessHours *businesshours.Calendar
+	// bot とみなすユーザーの glob パターン
+	Bots []string
+	// bot のレビュー・コメントも集計に含める。false の場合は bot の活動を別に集計する
+	IncludeBots bool
+	// 設定ファイルで追加した実績
+	Achievements []AchievementRule
+	// 集計しない PR の指標の名前
+	DisabledMetrics []string
+	// 当年に初めてやりとりした人を求めない。true の場合は前年以前の PR とレビューを取得しない
+	SkipNewCollaborators bool
+	// 比較対象の年。0 の場合は比較しない
+	CompareTo int
+	// OPEN のまま放置されているとみなす日数
+	StaleDays int
+	// API のレスポンスをキャッシュしない
+	NoCache  bool
+	Filter   Filter
+	location *time.Location
+	year     int
+}
+
+// PR を集計対象に含める / 除外する条件
+type Filter struct {
+	// owner/repo 形式の glob パターン。指定した場合はいずれかにマッチするリポジトリのみを対象にする
+	IncludeRepositories []string
+	// owner/repo 形式の glob パターン。いずれかにマッチするリポジトリを除外する
+	ExcludeRepositories []string
+	// OPEN / CLOSED / MERGED。指定した場合はいずれかの状態の PR のみを対象にする
+	States []string
+	// 指定した場合はいずれかのラベルが付いた PR のみを対象にする
+	IncludeLabels []string
+	// いずれかのラベルが付いた PR を除外する
+	ExcludeLabels []string
+	ExcludeDrafts bool
+	ExcludeForks  bool
+}
+
+// コマンドラインフラグの値。Load で設定ファイルの値と合成して Config を組み立てる
+type Flags struct {
+	fs                                                      *flag.FlagSet
+	filePath, profile, host, timezone, format, lifetimeFrom string
+	year, top, compareTo, staleDays                         int
+	debug, noCache, includeBots, skipNewCollaborators       bool
+	disabledMetrics                                         []string
+	filter                                                  Filter
+}
+
+// すべてのサブコマンドで共通のフラグを fs に登録する
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+
+	fs.StringVar(&f.filePath, "config", "", "path to the config file (default: $XDG_CONFIG_HOME/gh-wrapped/config.yml)")
+	fs.StringVar(&f.profile, "profile", "", "name of the profile in the config file to use")
+	fs.StringVar(&f.host, "hostname", "", "GitHub hostname (default: the host configured in gh)")
+	fs.StringVar(&f.timezone, "timezone", "", "time zone used to decide the boundary of the year (e.g. Asia/Tokyo)")
+	fs.StringVar(&f.format, "format", "", "output format: "+strings.Join(Formats, ", "))
+	fs.IntVar(&f.year, "year", 0, "year to wrap")
+	fs.IntVar(&f.compareTo, "compare-to", 0, "year to compare the pull request metrics with (e.g. the previous year)")
+	fs.IntVar(&f.top, "top", 0, "number of items in each ranking (overrides top in the config file; top_sections still apply)")
+	fs.IntVar(&f.staleDays, "stale-days", 0, "number of days after which an open pull request is considered stale (default: 30)")
+	fs.StringVar(&f.lifetimeFrom, "lifetime-from", "", "when the lifetime of a pull request starts: "+strings.Join(LifetimeFroms, ", ")+" (default: created)")
+	fs.BoolVar(&f.includeBots, "include-bots", false, "count reviews and comments by bots in the metrics")
+	fs.Var((*stringSliceFlag)(&f.disabledMetrics), "disable-metric", "name of the pull request metric not to compute (repeatable)")
+	fs.BoolVar(&f.skipNewCollaborators, "skip-new-collaborators", false, "do not look for collaborators you worked with for the first time (skips fetching the earlier years)")
+	fs.BoolVar(&f.debug, "debug", false, "enable debug logging (same as DEBUG=true)")
+	fs.BoolVar(&f.noCache, "no-cache", false, "do not read or write the API response cache")
+
+	return f
+}
+
+// PR の絞り込み条件のフラグを登録する
+func (f *Flags) RegisterFilterFlags() {
+	f.fs.Var((*stringSliceFlag)(&f.filter.IncludeRepositories), "include-repo", "owner/repo glob pattern of repositories to include (repeatable)")
+	f.fs.Var((*stringSliceFlag)(&f.filter.ExcludeRepositories), "exclude-repo", "owner/repo glob pattern of repositories to exclude (repeatable)")
+	f.fs.Var((*stringSliceFlag)(&f.filter.States), "state", "pull request state to include: OPEN, CLOSED or MERGED (repeatable)")
+	f.fs.Var((*stringSliceFlag)(&f.filter.IncludeLabels), "label", "label that pull requests must have (repeatable)")
+	f.fs.Var((*stringSliceFlag)(&f.filter.ExcludeLabels), "exclude-label", "label of pull requests to exclude (repeatable)")
+	f.fs.BoolVar(&f.filter.ExcludeDrafts, "exclude-drafts", false, "exclude draft pull requests")
+	f.fs.BoolVar(&f.filter.ExcludeForks, "exclude-forks", false, "exclude pull requests to forked repositories")
+}
+
+// 明示的に指定されたフラグかどうか
+func (f *Flags) IsSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// 設定ファイル → プロファイル → フラグの順に値を上書きして Config を組み立てる
+// FlagSet を Parse した後に呼ぶ。設定ファイルの実績は achievements で検証する
+func (f *Flags) Load(achievements AchievementValidator) (*Config, error) {
+	cfg := &Config{
+		DebugMode:    strings.ToUpper(os.Getenv("DEBUG")) == "TRUE",
+		Format:       "text",
+		Top:          3,
+		LifetimeFrom: LifetimeFromCreated,
+		StaleDays:    30,
+		location:     time.UTC,
+		year:         2023,
+	}
+
+	if f.filePath == "" {
+		defaultPath, err := DefaultFilePath()
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := loadFile(defaultPath, achievements)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			cfg.FilePath = defaultPath
+			if err := cfg.applyFile(file, f.profile); err != nil {
+				return nil, err
+			}
+		} else if f.profile != "" {
+			return nil, fmt.Errorf("--profile: config file %s does not exist", defaultPath)
+		}
+	} else {
+		file, err := loadFile(f.filePath, achievements)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			return nil, fmt.Errorf("--config: %s does not exist", f.filePath)
+		}
+
+		cfg.FilePath = f.filePath
+		if err := cfg.applyFile(file, f.profile); err != nil {
+			return nil, err
+		}
+	}
+
+	// 明示的に指定されたフラグだけで上書きする
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		switch fl.Name {
+		case "debug":
+			cfg.DebugMode = cfg.DebugMode || f.debug
+		case "no-cache":
+			cfg.NoCache = f.noCache
+		case "hostname":
+			cfg.Host = f.host
+		case "timezone":
+			loc, loadErr := time.LoadLocation(f.timezone)
+			if loadErr != nil {
+				err = fmt.Errorf("--timezone: unknown time zone %q", f.timezone)
+				return
+			}
+			cfg.location = loc
+		case "format":
+			if !lo.Contains(Formats, f.format) {
+				err = fmt.Errorf("--format: must be one of %s", strings.Join(Formats, ", "))
+				return
+			}
+			cfg.Format = f.format
+		case "top":
+			if f.top < 1 {
+				err = fmt.Errorf("--top: must be greater than 0")
+				return
+			}
+			// 件数の既定値だけを上書きし、top_sections で指定したランキングはその件数のままにする
+			cfg.Top = f.top
+		case "compare-to":
+			if f.compareTo < MinYear || f.compareTo > time.Now().Year() {
+				err = fmt.Errorf("--compare-to: must be between %d and %d", MinYear, time.Now().Year())
+				return
+			}
+			cfg.CompareTo = f.compareTo
+		case "stale-days":
+			if f.staleDays < 1 {
+				err = fmt.Errorf("--stale-days: must be greater than 0")
+				return
+			}
+			cfg.StaleDays = f.staleDays
+		case "include-bots":
+			cfg.IncludeBots = f.includeBots
+		case "disable-metric":
+			cfg.DisabledMetrics = f.disabledMetrics
+		case "skip-new-collaborators":
+			cfg.SkipNewCollaborators = f.skipNewCollaborators
+		case "lifetime-from":
+			if !lo.Contains(LifetimeFroms, f.lifetimeFrom) {
+				err = fmt.Errorf("--lifetime-from: must be one of %s", strings.Join(LifetimeFroms, ", "))
+				return
+			}
+			cfg.LifetimeFrom = f.lifetimeFrom
+		case "year":
+			if f.year < MinYear || f.year > time.Now().Year() {
+				err = fmt.Errorf("--year: must be between %d and %d", MinYear, time.Now().Year())
+				return
+			}
+			cfg.year = f.year
+		case "include-repo":
+			cfg.Filter.IncludeRepositories = f.filter.IncludeRepositories
+		case "exclude-repo":
+			cfg.Filter.ExcludeRepositories = f.filter.ExcludeRepositories
+		case "state":
+			cfg.Filter.States = f.filter.States
+		case "label":
+			cfg.Filter.IncludeLabels = f.filter.IncludeLabels
+		case "exclude-label":
+			cfg.Filter.ExcludeLabels = f.filter.ExcludeLabels
+		case "exclude-drafts":
+			cfg.Filter.ExcludeDrafts = f.filter.ExcludeDrafts
+		case "exclude-forks":
+			cfg.Filter.ExcludeForks = f.filter.ExcludeForks
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// business_hours.timezone が未指定の場合は年の境界と同じタイムゾーンにする
+	if cfg.BusinessHours != nil && cfg.BusinessHours.Location == nil {
+		cfg.BusinessHours.Location = cfg.location
+	}
+
+	return cfg, nil
 }
 
-func Parse() *Config {
-	debugMode := strings.ToUpper(os.Getenv("DEBUG")) == "TRUE"
+// 設定ファイルのトップレベルの値を適用した上で、プロファイルの値で上書きする
+// profile が空の場合は default_profile を使う
+func (c *Config) applyFile(file *fileConfig, profile string) error {
+	file.fileProfile.applyTo(c)
+
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+	if profile == "" {
+		return nil
+	}
+
+	p, ok := file.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("--profile: profile %q is not defined in %s", profile, c.FilePath)
+	}
+	p.applyTo(c)
+	c.Profile = profile
 
-	flag.Parse()
+	return nil
+}
 
-	return &Config{
-		DebugMode: debugMode,
-		year:      2023,
+// ranking に表示する件数
+func (c *Config) TopN(ranking string) int {
+	if n, ok := c.TopSections[ranking]; ok {
+		return n
 	}
+	if c.Top < 1 {
+		return 3
+	}
+	return c.Top
 }
 
 func (c *Config) Year() int {
@@ -33,3 +376,50 @@ func (c *Config) Year() int {
 func (c *Config) YearString() string {
 	return strconv.Itoa(c.Year())
 }
+
+// 集計対象の年だけを year に変えた Config を返す。比較対象の年は引き継がない
+func (c *Config) ForYear(year int) *Config {
+	copied := *c
+	copied.year = year
+	copied.CompareTo = 0
+
+	return &copied
+}
+
+// 年の境界を決めるタイムゾーン
+func (c *Config) Location() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// 集計期間の開始日時
+func (c *Config) From() time.Time {
+	return time.Date(c.Year(), time.January, 1, 0, 0, 0, 0, c.Location())
+}
+
+// 集計期間の終了日時
+func (c *Config) To() time.Time {
+	return time.Date(c.Year(), time.December, 31, 23, 59, 59, 0, c.Location())
+}
+
+// 複数回指定できる、もしくはカンマ区切りで指定できるフラグ
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,421 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// 設定ファイルの例
+//
+//	host: github.com
+//	timezone: Asia/Tokyo
+//	format: text
+//	top: 10
+//	top_sections:
+//	  short_live_pull_requests: 1
+//	lifetime_from: ready
+//	stale_days: 14
+//	bots: ["dependabot*", "renovate*"]
+//	include_bots: false
+//	disable_metrics: ["survival", "trends"]
+//	skip_new_collaborators: false
+//	filter:
+//	  exclude_repos: ["*/dotfiles", "*/sandbox"]
+//	  exclude_forks: true
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    host: github.example.com
+//	    filter:
+//	      include_repos: ["my-company/*"]
+//	  oss:
+//	    filter:
+//	      exclude_repos: ["my-company/*"]
+type fileConfig struct {
+	fileProfile    `yaml:",inline"`
+	DefaultProfile string                 `yaml:"default_profile"`
+	Profiles       map[string]fileProfile `yaml:"profiles"`
+}
+
+// トップレベルとプロファイルで共通の設定項目。未指定の項目は上書きしないためポインタにしている
+type fileProfile struct {
+	Host          *string            `yaml:"host"`
+	Timezone      *string            `yaml:"timezone"`
+	Year          *int               `yaml:"year"`
+	Format        *string            `yaml:"format"`
+	Top           *int               `yaml:"top"`
+	TopSections   map[string]int     `yaml:"top_sections"`
+	LifetimeFrom  *string            `yaml:"lifetime_from"`
+	StaleDays     *int               `yaml:"stale_days"`
+	BusinessHours *fileBusinessHours `yaml:"business_hours"`
+	Bots          []string           `yaml:"bots"`
+	Achievements  []AchievementRule  `yaml:"achievements"`
+	IncludeBots   *bool              `yaml:"include_bots"`
+	// 名前が正しいかは集計時に検証する
+	DisabledMetrics      []string    `yaml:"disable_metrics"`
+	SkipNewCollaborators *bool       `yaml:"skip_new_collaborators"`
+	Filter               *fileFilter `yaml:"filter"`
+}
+
+type fileFilter struct {
+	IncludeRepositories []string `yaml:"include_repos"`
+	ExcludeRepositories []string `yaml:"exclude_repos"`
+	States              []string `yaml:"states"`
+	IncludeLabels       []string `yaml:"include_labels"`
+	ExcludeLabels       []string `yaml:"exclude_labels"`
+	ExcludeDrafts       *bool    `yaml:"exclude_drafts"`
+	ExcludeForks        *bool    `yaml:"exclude_forks"`
+}
+
+// $XDG_CONFIG_HOME/gh-wrapped/config.yml (未設定の場合は ~/.config/gh-wrapped/config.yml)
+func DefaultFilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "gh-wrapped", "config.yml"), nil
+}
+
+// $XDG_CACHE_HOME/gh-wrapped (未設定の場合は ~/.cache/gh-wrapped)
+func DefaultCacheDir() (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+
+	return filepath.Join(dir, "gh-wrapped"), nil
+}
+
+// 設定ファイルを読み込んで検証する。ファイルが存在しない場合は nil を返す
+func loadFile(filePath string, achievements AchievementValidator) (*fileConfig, error) {
+	b, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	// エラーメッセージに行番号を含めるため、ノードとしても読み込んでおく
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	err = cfg.validate(achievements)
+	if err == nil {
+		err = cfg.loadHolidaysFiles(filepath.Dir(filePath))
+	}
+	if err != nil {
+		var keyErr *keyError
+		if errors.As(err, &keyErr) {
+			if line := lookupLine(&root, keyErr.key); line > 0 {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+			}
+		}
+
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	return &cfg, nil
+}
+
+// 設定ファイル中のどのキーが不正だったかを保持するエラー
+type keyError struct {
+	key []string
+	err error
+}
+
+func (e *keyError) Error() string {
+	return strings.Join(e.key, ".") + ": " + e.err.Error()
+}
+
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+func newKeyError(key []string, format string, args ...interface{}) error {
+	return &keyError{key: key, err: fmt.Errorf(format, args...)}
+}
+
+func (c *fileConfig) validate(achievements AchievementValidator) error {
+	if err := c.fileProfile.validate(nil, achievements); err != nil {
+		return err
+	}
+
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return newKeyError([]string{"default_profile"}, "profile %q is not defined", c.DefaultProfile)
+		}
+	}
+
+	// エラーの出る順番を安定させるため名前順に検証する
+	names := lo.Keys(c.Profiles)
+	sort.Strings(names)
+	for _, name := range names {
+		if err := c.Profiles[name].validate([]string{"profiles", name}, achievements); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// business_hours.holidays_file を読み込む。validate の後に呼ぶ
+func (c *fileConfig) loadHolidaysFiles(baseDir string) error {
+	if c.BusinessHours != nil {
+		if err := c.BusinessHours.loadHolidaysFile([]string{"business_hours"}, baseDir); err != nil {
+			return err
+		}
+	}
+
+	names := lo.Keys(c.Profiles)
+	sort.Strings(names)
+	for _, name := range names {
+		if b := c.Profiles[name].BusinessHours; b != nil {
+			if err := b.loadHolidaysFile([]string{"profiles", name, "business_hours"}, baseDir); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p fileProfile) validate(prefix []string, achievements AchievementValidator) error {
+	key := func(k ...string) []string {
+		return append(append([]string{}, prefix...), k...)
+	}
+
+	if p.Host != nil && strings.TrimSpace(*p.Host) == "" {
+		return newKeyError(key("host"), "must not be empty")
+	}
+
+	if p.Timezone != nil {
+		if _, err := time.LoadLocation(*p.Timezone); err != nil {
+			return newKeyError(key("timezone"), "unknown time zone %q", *p.Timezone)
+		}
+	}
+
+	if p.Year != nil && (*p.Year < MinYear || *p.Year > time.Now().Year()) {
+		return newKeyError(key("year"), "must be between %d and %d", MinYear, time.Now().Year())
+	}
+
+	if p.Format != nil && !lo.Contains(Formats, *p.Format) {
+		return newKeyError(key("format"), "must be one of %s", strings.Join(Formats, ", "))
+	}
+
+	if p.Top != nil && *p.Top < 1 {
+		return newKeyError(key("top"), "must be greater than 0")
+	}
+
+	sections := lo.Keys(p.TopSections)
+	sort.Strings(sections)
+	for _, section := range sections {
+		if !lo.Contains(Rankings, section) {
+			return newKeyError(key("top_sections", section), "unknown ranking, must be one of %s", strings.Join(Rankings, ", "))
+		}
+		if p.TopSections[section] < 1 {
+			return newKeyError(key("top_sections", section), "must be greater than 0")
+		}
+	}
+
+	if p.LifetimeFrom != nil && !lo.Contains(LifetimeFroms, *p.LifetimeFrom) {
+		return newKeyError(key("lifetime_from"), "must be one of %s", strings.Join(LifetimeFroms, ", "))
+	}
+
+	if p.StaleDays != nil && *p.StaleDays < 1 {
+		return newKeyError(key("stale_days"), "must be greater than 0")
+	}
+
+	for i, pattern := range p.Bots {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return newKeyError(key("bots", strconv.Itoa(i)), "invalid pattern %q", pattern)
+		}
+	}
+
+	if err := validateAchievementRules(p.Achievements, key("achievements"), achievements); err != nil {
+		return err
+	}
+
+	if p.BusinessHours != nil {
+		if err := p.BusinessHours.validate(key("business_hours")); err != nil {
+			return err
+		}
+	}
+
+	if p.Filter != nil {
+		return p.Filter.validate(key("filter"))
+	}
+
+	return nil
+}
+
+func (f fileFilter) validate(prefix []string) error {
+	key := func(k ...string) []string {
+		return append(append([]string{}, prefix...), k...)
+	}
+
+	patternsByName := map[string][]string{
+		"include_repos": f.IncludeRepositories,
+		"exclude_repos": f.ExcludeRepositories,
+	}
+	// エラーの出る順番を安定させるため名前順に検証する
+	names := lo.Keys(patternsByName)
+	sort.Strings(names)
+	for _, name := range names {
+		for i, pattern := range patternsByName[name] {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return newKeyError(key(name, strconv.Itoa(i)), "invalid pattern %q", pattern)
+			}
+		}
+	}
+
+	for i, state := range f.States {
+		if !lo.Contains(pullRequestStates, strings.ToUpper(state)) {
+			return newKeyError(key("states", strconv.Itoa(i)), "must be one of %s", strings.Join(pullRequestStates, ", "))
+		}
+	}
+
+	return nil
+}
+
+// fileProfile の指定されている項目で Config を上書きする
+func (p fileProfile) applyTo(c *Config) {
+	if p.Host != nil {
+		c.Host = *p.Host
+	}
+	if p.Timezone != nil {
+		// validate で検証済み
+		c.location = lo.Must(time.LoadLocation(*p.Timezone))
+	}
+	if p.Year != nil {
+		c.year = *p.Year
+	}
+	if p.Format != nil {
+		c.Format = *p.Format
+	}
+	if p.Top != nil {
+		c.Top = *p.Top
+	}
+	for section, n := range p.TopSections {
+		if c.TopSections == nil {
+			c.TopSections = map[string]int{}
+		}
+		c.TopSections[section] = n
+	}
+	if p.LifetimeFrom != nil {
+		c.LifetimeFrom = *p.LifetimeFrom
+	}
+	if p.StaleDays != nil {
+		c.StaleDays = *p.StaleDays
+	}
+	if p.Bots != nil {
+		c.Bots = p.Bots
+	}
+	if p.Achievements != nil {
+		c.Achievements = p.Achievements
+	}
+	if p.IncludeBots != nil {
+		c.IncludeBots = *p.IncludeBots
+	}
+	if p.DisabledMetrics != nil {
+		c.DisabledMetrics = p.DisabledMetrics
+	}
+	if p.SkipNewCollaborators != nil {
+		c.SkipNewCollaborators = *p.SkipNewCollaborators
+	}
+	if p.BusinessHours != nil {
+		c.BusinessHours = p.BusinessHours.calendar()
+	}
+	if p.Filter != nil {
+		if p.Filter.IncludeRepositories != nil {
+			c.Filter.IncludeRepositories = p.Filter.IncludeRepositories
+		}
+		if p.Filter.ExcludeRepositories != nil {
+			c.Filter.ExcludeRepositories = p.Filter.ExcludeRepositories
+		}
+		if p.Filter.States != nil {
+			c.Filter.States = p.Filter.States
+		}
+		if p.Filter.IncludeLabels != nil {
+			c.Filter.IncludeLabels = p.Filter.IncludeLabels
+		}
+		if p.Filter.ExcludeLabels != nil {
+			c.Filter.ExcludeLabels = p.Filter.ExcludeLabels
+		}
+		if p.Filter.ExcludeDrafts != nil {
+			c.Filter.ExcludeDrafts = *p.Filter.ExcludeDrafts
+		}
+		if p.Filter.ExcludeForks != nil {
+			c.Filter.ExcludeForks = *p.Filter.ExcludeForks
+		}
+	}
+}
+
+// key で指定したノードの行番号を返す。見つからない場合は 0
+func lookupLine(root *yaml.Node, key []string) int {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := node.Line
+	for _, k := range key {
+		switch node.Kind {
+		case yaml.MappingNode:
+			// Content はキーと値が交互に並んでいる
+			var value *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == k {
+					line = node.Content[i].Line
+					value = node.Content[i+1]
+					break
+				}
+			}
+			if value == nil {
+				return line
+			}
+			node = value
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(k)
+			if err != nil || i >= len(node.Content) {
+				return line
+			}
+			node = node.Content[i]
+			line = node.Line
+		default:
+			return line
+		}
+	}
+
+	return line
+}
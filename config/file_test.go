@@ -0,0 +1,262 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadFile_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// ファイルパスを除いたエラーメッセージの先頭
+		wantErr string
+	}{
+		{
+			name:    "key with line number",
+			content: "format: text\ntop: 0\n",
+			wantErr: ":2: top: must be greater than 0",
+		},
+		{
+			name:    "unknown field",
+			content: "unknown: 1\n",
+			wantErr: ": yaml: unmarshal errors:\n  line 1: field unknown not found",
+		},
+		{
+			name:    "sequence element",
+			content: "filter:\n  exclude_repos:\n    - \"*/ok\"\n    - \"[\"\n",
+			wantErr: `:4: filter.exclude_repos.1: invalid pattern "["`,
+		},
+		{
+			name:    "several invalid filter keys are reported in name order",
+			content: "filter:\n  include_repos: [\"[\"]\n  exclude_repos: [\"[\"]\n",
+			wantErr: `:3: filter.exclude_repos.0: invalid pattern "["`,
+		},
+		{
+			name:    "undefined default profile",
+			content: "default_profile: work\n",
+			wantErr: `:1: default_profile: profile "work" is not defined`,
+		},
+		{
+			name:    "key in profile",
+			content: "profiles:\n  work:\n    top_sections:\n      unknown_ranking: 1\n",
+			wantErr: ":4: profiles.work.top_sections.unknown_ranking: unknown ranking",
+		},
+		{
+			name:    "profiles are validated in name order",
+			content: "profiles:\n  work:\n    top: 0\n  oss:\n    stale_days: 0\n",
+			wantErr: ":5: profiles.oss.stale_days: must be greater than 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.content)
+
+			_, err := loadFile(path, AchievementValidator{})
+			if err == nil {
+				t.Fatal("loadFile() error = nil")
+			}
+			if got := strings.TrimPrefix(err.Error(), path); !strings.HasPrefix(got, tt.wantErr) {
+				t.Errorf("loadFile() error = %q, want prefix %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFile_achievements(t *testing.T) {
+	validator := AchievementValidator{
+		ReservedNames: []string{"Gatekeeper"},
+		ValidateMetric: func(metric string) error {
+			if metric != "Reviews.TotalCount" {
+				return fmt.Errorf("unknown metric %q", metric)
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "unknown metric",
+			content: "achievements:\n  - name: ok\n    metric: Reviews.TotalCount\n  - name: typo\n    metric: Reviews.TotalCont\n",
+			want:    `:5: achievements.1.metric: unknown metric "Reviews.TotalCont"`,
+		},
+		{
+			name:    "name of a built-in achievement",
+			content: "achievements:\n  - name: gatekeeper\n    metric: Reviews.TotalCount\n",
+			want:    `:2: achievements.0.name: "gatekeeper" is a built-in achievement`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.content)
+
+			_, err := loadFile(path, validator)
+			if err == nil || strings.TrimPrefix(err.Error(), path) != tt.want {
+				t.Errorf("loadFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile_notExist(t *testing.T) {
+	cfg, err := loadFile(filepath.Join(t.TempDir(), "config.yml"), AchievementValidator{})
+	if cfg != nil || err != nil {
+		t.Errorf("loadFile() = %v, %v, want nil, nil", cfg, err)
+	}
+}
+
+func TestFlags_Load_profiles(t *testing.T) {
+	path := writeConfigFile(t, `
+top: 5
+stale_days: 14
+filter:
+  exclude_repos: ["*/dotfiles"]
+  exclude_forks: true
+default_profile: work
+profiles:
+  work:
+    host: github.example.com
+    top: 10
+    filter:
+      include_repos: ["my-company/*"]
+  oss:
+    top_sections:
+      top_bots: 1
+    filter:
+      exclude_repos: ["my-company/*"]
+`)
+
+	type want struct {
+		profile   string
+		host      string
+		top       int
+		topBots   int
+		staleDays int
+		filter    Filter
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    want
+		wantErr string
+	}{
+		{
+			name: "default profile overrides the top level",
+			args: nil,
+			want: want{
+				profile:   "work",
+				host:      "github.example.com",
+				top:       10,
+				topBots:   10,
+				staleDays: 14,
+				filter: Filter{
+					IncludeRepositories: []string{"my-company/*"},
+					ExcludeRepositories: []string{"*/dotfiles"},
+					ExcludeForks:        true,
+				},
+			},
+		},
+		{
+			name: "selected profile",
+			args: []string{"--profile", "oss"},
+			want: want{
+				profile:   "oss",
+				top:       5,
+				topBots:   1,
+				staleDays: 14,
+				filter: Filter{
+					ExcludeRepositories: []string{"my-company/*"},
+					ExcludeForks:        true,
+				},
+			},
+		},
+		{
+			name: "flags override the profile but keep top_sections",
+			args: []string{"--profile", "oss", "--top", "2", "--stale-days", "7", "--exclude-repo", "a/b"},
+			want: want{
+				profile:   "oss",
+				top:       2,
+				topBots:   1,
+				staleDays: 7,
+				filter: Filter{
+					ExcludeRepositories: []string{"a/b"},
+					ExcludeForks:        true,
+				},
+			},
+		},
+		{
+			name: "--top applies to rankings without top_sections",
+			args: []string{"--top", "2"},
+			want: want{
+				profile:   "work",
+				host:      "github.example.com",
+				top:       2,
+				topBots:   2,
+				staleDays: 14,
+				filter: Filter{
+					IncludeRepositories: []string{"my-company/*"},
+					ExcludeRepositories: []string{"*/dotfiles"},
+					ExcludeForks:        true,
+				},
+			},
+		},
+		{
+			name:    "undefined profile",
+			args:    []string{"--profile", "home"},
+			wantErr: `--profile: profile "home" is not defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := RegisterFlags(fs)
+			flags.RegisterFilterFlags()
+			if err := fs.Parse(append([]string{"--config", path}, tt.args...)); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			cfg, err := flags.Load(AchievementValidator{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want prefix %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			got := want{
+				profile:   cfg.Profile,
+				host:      cfg.Host,
+				top:       cfg.Top,
+				topBots:   cfg.TopN(RankingTopBots),
+				staleDays: cfg.StaleDays,
+				filter:    cfg.Filter,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// ListPullRequests で取得した PR を WrapPullRequest に渡す前に絞り込む
+type PullRequestFilter struct {
+	includeRepositories []string
+	excludeRepositories []string
+	states              []repository.PullRequestState
+	includeLabels       []string
+	excludeLabels       []string
+	excludeDrafts       bool
+	excludeForks        bool
+}
+
+// 除外された PR の数。PR が複数の条件に該当する場合は最初に該当した条件でのみ数える
+type Stats struct {
+	// 絞り込み前の PR の数
+	TotalCount int
+	// 絞り込み後に残った PR の数
+	KeptCount            int
+	ExcludedByRepository int
+	ExcludedByFork       int
+	ExcludedByState      int
+	ExcludedByDraft      int
+	ExcludedByLabel      int
+}
+
+func (s Stats) ExcludedCount() int {
+	return s.TotalCount - s.KeptCount
+}
+
+func New(cfg config.Filter) (*PullRequestFilter, error) {
+	for _, pattern := range lo.Flatten([][]string{cfg.IncludeRepositories, cfg.ExcludeRepositories}) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+
+	var states []repository.PullRequestState
+	for _, s := range cfg.States {
+		switch state := repository.PullRequestState(strings.ToUpper(s)); state {
+		case repository.PullRequestStateOpen, repository.PullRequestStateClosed, repository.PullRequestStateMerged:
+			states = append(states, state)
+		default:
+			return nil, fmt.Errorf("invalid pull request state %q", s)
+		}
+	}
+
+	return &PullRequestFilter{
+		includeRepositories: lowerAll(cfg.IncludeRepositories),
+		excludeRepositories: lowerAll(cfg.ExcludeRepositories),
+		states:              states,
+		includeLabels:       lowerAll(cfg.IncludeLabels),
+		excludeLabels:       lowerAll(cfg.ExcludeLabels),
+		excludeDrafts:       cfg.ExcludeDrafts,
+		excludeForks:        cfg.ExcludeForks,
+	}, nil
+}
+
+func (f *PullRequestFilter) Apply(pullRequests []*repository.PullRequest) ([]*repository.PullRequest, Stats) {
+	stats := Stats{TotalCount: len(pullRequests)}
+
+	kept := lo.Filter(pullRequests, func(pr *repository.PullRequest, _ int) bool {
+		switch {
+		case !f.matchRepository(pr):
+			stats.ExcludedByRepository++
+		case f.excludeForks && pr.RepositoryIsFork:
+			stats.ExcludedByFork++
+		case len(f.states) > 0 && !lo.Contains(f.states, pr.State):
+			stats.ExcludedByState++
+		case f.excludeDrafts && pr.IsDraft:
+			stats.ExcludedByDraft++
+		case !f.matchLabels(pr):
+			stats.ExcludedByLabel++
+		default:
+			return true
+		}
+
+		return false
+	})
+	stats.KeptCount = len(kept)
+
+	return kept, stats
+}
+
+func (f *PullRequestFilter) matchRepository(pr *repository.PullRequest) bool {
+	// GitHub の owner / repo は大文字小文字を区別しない
+	name := strings.ToLower(pr.RepositoryFullName())
+
+	if len(f.includeRepositories) > 0 && !matchAny(f.includeRepositories, name) {
+		return false
+	}
+
+	return !matchAny(f.excludeRepositories, name)
+}
+
+func (f *PullRequestFilter) matchLabels(pr *repository.PullRequest) bool {
+	labels := lowerAll(pr.Labels)
+
+	if len(f.includeLabels) > 0 && !lo.Some(labels, f.includeLabels) {
+		return false
+	}
+
+	return !lo.Some(labels, f.excludeLabels)
+}
+
+func matchAny(patterns []string, name string) bool {
+	return lo.SomeBy(patterns, func(pattern string) bool {
+		// パターンは New で検証済み
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+func lowerAll(values []string) []string {
+	return lo.Map(values, func(v string, _ int) string {
+		return strings.ToLower(v)
+	})
+}
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Filter
+		wantErr bool
+	}{
+		{name: "empty", cfg: config.Filter{}},
+		{name: "valid patterns and states", cfg: config.Filter{
+			IncludeRepositories: []string{"my-org/*"},
+			ExcludeRepositories: []string{"*/dotfiles"},
+			States:              []string{"open", "MERGED"},
+		}},
+		{name: "invalid include pattern", cfg: config.Filter{IncludeRepositories: []string{"my-org/["}}, wantErr: true},
+		{name: "invalid exclude pattern", cfg: config.Filter{ExcludeRepositories: []string{"["}}, wantErr: true},
+		{name: "invalid state", cfg: config.Filter{States: []string{"DRAFT"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPullRequestFilter_Apply(t *testing.T) {
+	pullRequests := []*repository.PullRequest{
+		{URL: "1", RepositoryOwner: "My-Org", RepositoryName: "api", State: repository.PullRequestStateMerged, Labels: []string{"Feature"}},
+		{URL: "2", RepositoryOwner: "my-org", RepositoryName: "dotfiles", State: repository.PullRequestStateOpen},
+		{URL: "3", RepositoryOwner: "someone", RepositoryName: "lib", RepositoryIsFork: true, State: repository.PullRequestStateClosed},
+		{URL: "4", RepositoryOwner: "someone", RepositoryName: "app", State: repository.PullRequestStateOpen, IsDraft: true},
+		{URL: "5", RepositoryOwner: "someone", RepositoryName: "app", State: repository.PullRequestStateMerged, Labels: []string{"wip"}},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       config.Filter
+		wantURLs  []string
+		wantStats Stats
+	}{
+		{
+			name:      "no filter",
+			cfg:       config.Filter{},
+			wantURLs:  []string{"1", "2", "3", "4", "5"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 5},
+		},
+		{
+			name:      "include repositories case-insensitively",
+			cfg:       config.Filter{IncludeRepositories: []string{"MY-ORG/*"}},
+			wantURLs:  []string{"1", "2"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 2, ExcludedByRepository: 3},
+		},
+		{
+			name:      "exclude repositories",
+			cfg:       config.Filter{ExcludeRepositories: []string{"*/dotfiles", "someone/a*"}},
+			wantURLs:  []string{"1", "3"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 2, ExcludedByRepository: 3},
+		},
+		{
+			name:      "glob does not cross the slash",
+			cfg:       config.Filter{IncludeRepositories: []string{"*"}},
+			wantURLs:  nil,
+			wantStats: Stats{TotalCount: 5, KeptCount: 0, ExcludedByRepository: 5},
+		},
+		{
+			name:      "states",
+			cfg:       config.Filter{States: []string{"open"}},
+			wantURLs:  []string{"2", "4"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 2, ExcludedByState: 3},
+		},
+		{
+			name:      "labels",
+			cfg:       config.Filter{IncludeLabels: []string{"feature", "WIP"}, ExcludeLabels: []string{"wip"}},
+			wantURLs:  []string{"1"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 1, ExcludedByLabel: 4},
+		},
+		{
+			name:      "first matching condition is counted",
+			cfg:       config.Filter{ExcludeRepositories: []string{"my-org/dotfiles"}, ExcludeForks: true, ExcludeDrafts: true, States: []string{"MERGED", "OPEN"}},
+			wantURLs:  []string{"1", "5"},
+			wantStats: Stats{TotalCount: 5, KeptCount: 2, ExcludedByRepository: 1, ExcludedByFork: 1, ExcludedByDraft: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			kept, stats := f.Apply(pullRequests)
+
+			var urls []string
+			for _, pr := range kept {
+				urls = append(urls, pr.URL)
+			}
+			if !reflect.DeepEqual(urls, tt.wantURLs) {
+				t.Errorf("Apply() kept %v, want %v", urls, tt.wantURLs)
+			}
+			if stats != tt.wantStats {
+				t.Errorf("Apply() stats = %+v, want %+v", stats, tt.wantStats)
+			}
+			if stats.ExcludedCount() != stats.TotalCount-len(kept) {
+				t.Errorf("ExcludedCount() = %d, want %d", stats.ExcludedCount(), stats.TotalCount-len(kept))
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ require (
 	github.com/montanaflynn/stats v0.7.1
 	github.com/samber/lo v1.39.0
 	github.com/volatiletech/null/v8 v8.1.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -38,5 +39,4 @@ require (
 	golang.org/x/term v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 	golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -1,22 +1,19 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
+	"os"
 	"runtime"
 	"strings"
 
-	"github.com/kmtym1998/gh-wrapped/config"
-	"github.com/kmtym1998/gh-wrapped/repository"
-	"github.com/kmtym1998/gh-wrapped/wrapper"
-	"github.com/kr/pretty"
-	"github.com/m-mizutani/clog"
+	"github.com/kmtym1998/gh-wrapped/command"
 	"github.com/samber/lo"
 )
 
 func main() {
-	cfg := config.Parse()
-
 	// TODO: kmtym1998/handyman への以降
 	defer func() {
 		if err := recover(); err != nil {
@@ -57,38 +54,21 @@ func main() {
 		}
 	}()
 
-	setupLogger(cfg)
-
-	repo, err := repository.NewGitHub()
-	if err != nil {
-		fatal("failed to create GitHub client: %v", err)
-	}
-
-	pr, err := wrapper.WrapPullRequest(repo, cfg)
-	if err != nil {
-		fatal("failed to wrap pull requests: %v", err)
+	if err := command.Run(os.Args[1:]); err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			os.Exit(0)
+		case errors.Is(err, command.ErrUsage):
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		default:
+			fatal("failed to run command: %v", err)
+		}
 	}
-	pretty.Println(pr)
-}
-
-func setupLogger(cfg *config.Config) {
-	logger := slog.New(
-		clog.New(
-			clog.WithSource(cfg.DebugMode),
-			clog.WithLevel(
-				lo.Ternary(
-					cfg.DebugMode,
-					slog.LevelDebug,
-					slog.LevelInfo,
-				),
-			),
-		),
-	)
-
-	slog.SetDefault(logger)
 }
 
 func fatal(msg string, args ...interface{}) {
-	slog.Error(fmt.Sprintf(msg, args...))
+	msg = fmt.Sprintf(msg, args...)
+	slog.Error(msg)
 	panic(msg)
 }
@@ -0,0 +1,144 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+// コラボレーショングラフの出力形式
+var GraphFormats = []string{"dot", "graphml"}
+
+var collaborationGraphType = reflect.TypeOf(wrapper.CollaborationGraph{})
+
+// エッジの種類ごとの色
+var collaborationKindColors = map[wrapper.CollaborationKind]string{
+	wrapper.CollaborationKindReview: "#26a641",
+	wrapper.CollaborationKindReply:  "#58a6ff",
+}
+
+// graph を format で指定した形式で w に書き出す
+func RenderGraph(w io.Writer, format string, graph wrapper.CollaborationGraph) error {
+	switch format {
+	case "dot":
+		return renderDOT(w, graph)
+	case "graphml":
+		return renderGraphML(w, graph)
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+}
+
+// text / html ではグラフの大きさだけを表示する
+func formatCollaborationGraph(graph wrapper.CollaborationGraph) string {
+	return fmt.Sprintf("%d people, %d edges (export with gh wrapped graph)", len(graph.Nodes), len(graph.Edges))
+}
+
+func renderDOT(w io.Writer, graph wrapper.CollaborationGraph) error {
+	var b strings.Builder
+	b.WriteString("digraph collaboration {\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s;\n", strconv.Quote(node))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(
+			&b,
+			"  %s -> %s [label=%s, weight=%d, penwidth=%d, color=%s];\n",
+			strconv.Quote(edge.From),
+			strconv.Quote(edge.To),
+			strconv.Quote(fmt.Sprintf("%s ×%d", edge.Kind, edge.Weight)),
+			edge.Weight,
+			min(edge.Weight, 10),
+			strconv.Quote(collaborationKindColors[edge.Kind]),
+		)
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write dot: %w", err)
+	}
+
+	return nil
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID string `xml:"id,attr"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func renderGraphML(w io.Writer, graph wrapper.CollaborationGraph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "kind", For: "edge", AttrName: "kind", AttrType: "string"},
+			{ID: "weight", For: "edge", AttrName: "weight", AttrType: "int"},
+		},
+		Graph: graphMLGraph{ID: "collaboration", EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node})
+	}
+	for _, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.From,
+			Target: edge.To,
+			Data: []graphMLData{
+				{Key: "kind", Value: string(edge.Kind)},
+				{Key: "weight", Value: strconv.Itoa(edge.Weight)},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write graphml: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode graphml: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write graphml: %w", err)
+	}
+
+	return nil
+}
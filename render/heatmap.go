@@ -0,0 +1,109 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+var heatmapType = reflect.TypeOf(wrapper.ContributionHeatmap{})
+
+// 色の濃さごとの文字と色 (GitHub のダークテーマ)
+var (
+	heatmapBlocks = []string{"·", "░", "▒", "▓", "█"}
+	heatmapColors = []string{"#161b22", "#0e4429", "#006d32", "#26a641", "#39d353"}
+)
+
+const (
+	heatmapCellSize = 11
+	heatmapCellStep = 14
+	// 曜日のラベルの幅
+	heatmapLabelWidth = 30
+	// 月のラベルの高さ
+	heatmapHeaderHeight = 20
+)
+
+// 曜日の行ごとに Unicode のブロック文字でヒートマップを描く
+func heatmapLines(heatmap wrapper.ContributionHeatmap) []string {
+	if len(heatmap.Weeks) == 0 {
+		return []string{"-"}
+	}
+
+	header := []rune(strings.Repeat(" ", len(heatmap.Weeks)+4))
+	rows := make([][]string, 7)
+	for i := range rows {
+		rows[i] = make([]string, len(heatmap.Weeks))
+		for j := range rows[i] {
+			rows[i][j] = " "
+		}
+	}
+
+	for i, week := range heatmap.Weeks {
+		for _, day := range week.Days {
+			rows[day.Weekday][i] = heatmapBlocks[day.Level]
+
+			// 月初を含む週の上に月のラベルを置く
+			if strings.HasSuffix(day.Date, "-01") {
+				month, _ := time.Parse("2006-01-02", day.Date)
+				copy(header[i+4:], []rune(month.Format("Jan")))
+			}
+		}
+	}
+
+	lines := []string{strings.TrimRight(string(header), " ")}
+	for weekday, row := range rows {
+		label := "   "
+		if weekday%2 == 1 {
+			label = time.Weekday(weekday).String()[:3]
+		}
+		lines = append(lines, label+" "+strings.Join(row, ""))
+	}
+	lines = append(lines, "    Less "+strings.Join(heatmapBlocks, " ")+" More")
+
+	return lines
+}
+
+// ヒートマップの幅と高さ
+func heatmapSize(heatmap wrapper.ContributionHeatmap) (int, int) {
+	return heatmapLabelWidth + len(heatmap.Weeks)*heatmapCellStep, heatmapHeaderHeight + 7*heatmapCellStep
+}
+
+// (x, y) を左上にしてヒートマップを SVG の要素で描く
+func heatmapSVG(heatmap wrapper.ContributionHeatmap, x, y int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<g transform="translate(%d,%d)" font-family="sans-serif" font-size="9" fill="#8b949e">`, x, y)
+
+	for weekday := 1; weekday < 7; weekday += 2 {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, heatmapHeaderHeight+weekday*heatmapCellStep+9, time.Weekday(weekday).String()[:3])
+	}
+
+	for i, week := range heatmap.Weeks {
+		for _, day := range week.Days {
+			cellX := heatmapLabelWidth + i*heatmapCellStep
+			if strings.HasSuffix(day.Date, "-01") {
+				month, _ := time.Parse("2006-01-02", day.Date)
+				fmt.Fprintf(&b, `<text x="%d" y="12">%s</text>`, cellX, month.Format("Jan"))
+			}
+
+			fmt.Fprintf(
+				&b,
+				`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d contributions</title></rect>`,
+				cellX,
+				heatmapHeaderHeight+int(day.Weekday)*heatmapCellStep,
+				heatmapCellSize,
+				heatmapCellSize,
+				heatmapColors[day.Level],
+				html.EscapeString(day.Date),
+				day.Count,
+			)
+		}
+	}
+
+	b.WriteString("</g>")
+
+	return b.String()
+}
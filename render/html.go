@@ -0,0 +1,167 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+var htmlTemplate = template.Must(template.New("wrapped").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>@{{ .Login }}'s {{ .Year }} wrapped</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; background: #0d1117; color: #e6edf3; margin: 0 auto; max-width: 960px; padding: 2rem; }
+h1 { font-size: 2rem; }
+h2 { border-bottom: 1px solid #30363d; padding-bottom: .3rem; margin-top: 2.5rem; }
+a { color: #58a6ff; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .25rem 1rem; margin: 0; }
+dt { color: #8b949e; }
+dd { margin: 0; }
+ol { margin: 0; padding-left: 1.5rem; }
+li { margin-bottom: .5rem; }
+</style>
+</head>
+<body>
+<h1>@{{ .Login }}'s {{ .Year }} wrapped</h1>
+{{ range .Sections }}<section>
+<h2>{{ .Name }}</h2>
+{{ .Body }}
+</section>
+{{ end }}</body>
+</html>
+`))
+
+type htmlSection struct {
+	Name string
+	Body template.HTML
+}
+
+// 集計結果を 1 枚の HTML ページとして書き出す
+func renderHTML(w io.Writer, result *wrapper.WrappedResult) error {
+	var htmlSections []htmlSection
+	for _, section := range sections(result) {
+		h := &htmlWriter{}
+		h.fields(section.value)
+
+		htmlSections = append(htmlSections, htmlSection{
+			Name: section.name,
+			// htmlWriter で値はすべてエスケープしている
+			Body: template.HTML(h.String()),
+		})
+	}
+
+	if err := htmlTemplate.Execute(w, map[string]interface{}{
+		"Login":    result.Login,
+		"Year":     result.Year,
+		"Sections": htmlSections,
+	}); err != nil {
+		return fmt.Errorf("failed to execute html template: %w", err)
+	}
+
+	return nil
+}
+
+type htmlWriter struct {
+	strings.Builder
+}
+
+func (h *htmlWriter) fields(v reflect.Value) {
+	h.WriteString("<dl>")
+	for _, f := range visibleFields(v) {
+		h.WriteString("<dt>" + html.EscapeString(f.name) + "</dt><dd>")
+		h.value(f.value)
+		h.WriteString("</dd>")
+	}
+	h.WriteString("</dl>")
+}
+
+func (h *htmlWriter) value(v reflect.Value) {
+	if v.Type() == simplePullRequestType {
+		pr := v.Interface().(wrapper.SimplePullRequest)
+		fmt.Fprintf(h, `<a href="%s">%s</a>`, html.EscapeString(pr.URL), html.EscapeString(formatSimplePullRequest(pr)))
+		return
+	}
+
+	if v.Type() == heatmapType {
+		heatmap := v.Interface().(wrapper.ContributionHeatmap)
+		width, height := heatmapSize(heatmap)
+		fmt.Fprintf(h, `<svg width="%d" height="%d">%s</svg>`, width, height, heatmapSVG(heatmap, 0, 0))
+		return
+	}
+
+	if v.Type() == activityHistogramType {
+		h.WriteString(activityHistogramSVG(v.Interface().(wrapper.ActivityHistogram)))
+		return
+	}
+
+	if v.Type() == survivalCurveType {
+		fmt.Fprintf(h, `<svg width="%d" height="%d">%s</svg>`, survivalChartWidth, survivalChartHeight, survivalCurveSVG(v.Interface().(wrapper.SurvivalCurve), 0, 0))
+		return
+	}
+
+	if v.Type() == simpleIssueType {
+		issue := v.Interface().(wrapper.SimpleIssue)
+		fmt.Fprintf(h, `<a href="%s">%s</a>`, html.EscapeString(issue.URL), html.EscapeString(formatSimpleIssue(issue)))
+		return
+	}
+
+	if s, ok := scalarString(v); ok {
+		h.WriteString(html.EscapeString(s))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		h.fields(v)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			h.WriteString("-")
+			return
+		}
+		if s, ok := scalarSliceString(v); ok {
+			h.WriteString(html.EscapeString(s))
+			return
+		}
+
+		h.WriteString("<ol>")
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := indirect(v.Index(i))
+			if !ok {
+				continue
+			}
+			h.WriteString("<li>")
+			h.value(elem)
+			h.WriteString("</li>")
+		}
+		h.WriteString("</ol>")
+	case reflect.Map:
+		if v.Len() == 0 {
+			h.WriteString("-")
+			return
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		h.WriteString("<dl>")
+		for _, key := range keys {
+			value, ok := indirect(v.MapIndex(key))
+			if !ok {
+				continue
+			}
+			h.WriteString("<dt>" + html.EscapeString(fmt.Sprint(key)) + "</dt><dd>")
+			h.value(value)
+			h.WriteString("</dd>")
+		}
+		h.WriteString("</dl>")
+	}
+}
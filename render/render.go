@@ -0,0 +1,70 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+// result を format で指定した形式で w に書き出す
+func Render(w io.Writer, format string, result *wrapper.WrappedResult) error {
+	switch format {
+	case "text":
+		return renderText(w, result)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode json: %w", err)
+		}
+	case "html":
+		return renderHTML(w, result)
+	case "svg":
+		return renderSVG(w, result)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return nil
+}
+
+// 出力形式ごとのファイルの拡張子
+func Extension(format string) string {
+	switch format {
+	case "text":
+		return ".txt"
+	default:
+		return "." + format
+	}
+}
+
+type section struct {
+	name  string
+	value reflect.Value
+}
+
+// WrappedResult のうち集計されたセクションを定義順に返す
+func sections(result *wrapper.WrappedResult) []section {
+	var sections []section
+	v := reflect.ValueOf(result).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Pointer {
+			continue
+		}
+
+		value, ok := indirect(v.Field(i))
+		if !ok || value.Kind() != reflect.Struct {
+			continue
+		}
+
+		sections = append(sections, section{
+			name:  humanize(v.Type().Field(i).Name),
+			value: value,
+		})
+	}
+
+	return sections
+}
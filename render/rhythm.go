@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+var activityHistogramType = reflect.TypeOf(wrapper.ActivityHistogram{})
+
+const (
+	// 時間のラベルを置く間隔
+	activityHourLabelStep = 6
+	// 時間のラベルの高さ
+	activityHeaderHeight = 14
+)
+
+// 曜日の行ごとに、0 時から 23 時までの活動量をブロック文字で描く
+func activityHistogramLines(histogram wrapper.ActivityHistogram) []string {
+	header := []rune(strings.Repeat(" ", 4+24))
+	for hour := 0; hour < 24; hour += activityHourLabelStep {
+		copy(header[4+hour:], []rune(fmt.Sprint(hour)))
+	}
+
+	lines := []string{strings.TrimRight(string(header), " ")}
+	for weekday, levels := range histogram.Levels {
+		var row strings.Builder
+		for _, level := range levels {
+			row.WriteString(heatmapBlocks[level])
+		}
+		lines = append(lines, time.Weekday(weekday).String()[:3]+" "+row.String())
+	}
+
+	return lines
+}
+
+// 曜日 × 時間のマスを SVG で描く
+func activityHistogramSVG(histogram wrapper.ActivityHistogram) string {
+	width := heatmapLabelWidth + 24*heatmapCellStep
+	height := activityHeaderHeight + 7*heatmapCellStep
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg width="%d" height="%d"><g font-family="sans-serif" font-size="9" fill="#8b949e">`, width, height)
+
+	for hour := 0; hour < 24; hour += activityHourLabelStep {
+		fmt.Fprintf(&b, `<text x="%d" y="10">%d</text>`, heatmapLabelWidth+hour*heatmapCellStep, hour)
+	}
+
+	for weekday, levels := range histogram.Levels {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, activityHeaderHeight+weekday*heatmapCellStep+9, time.Weekday(weekday).String()[:3])
+
+		for hour, level := range levels {
+			fmt.Fprintf(
+				&b,
+				`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s %02d:00: %d</title></rect>`,
+				heatmapLabelWidth+hour*heatmapCellStep,
+				activityHeaderHeight+weekday*heatmapCellStep,
+				heatmapCellSize,
+				heatmapCellSize,
+				heatmapColors[level],
+				time.Weekday(weekday).String(),
+				hour,
+				histogram.Counts[weekday][hour],
+			)
+		}
+	}
+
+	b.WriteString("</g></svg>")
+
+	return b.String()
+}
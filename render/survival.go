@@ -0,0 +1,135 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+var survivalCurveType = reflect.TypeOf(wrapper.SurvivalCurve{})
+
+// テキストで曲線の値を表示する経過時間
+var survivalMilestones = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	90 * 24 * time.Hour,
+}
+
+const (
+	survivalChartWidth  = 445
+	survivalChartHeight = 120
+	// 軸のラベルの幅と高さ
+	survivalAxisWidth  = 35
+	survivalAxisHeight = 14
+)
+
+var (
+	survivalMergedColor = "#26a641"
+	survivalOpenColor   = "#8b949e"
+	survivalClosedColor = "#f85149"
+)
+
+// 経過時間ごとに、マージされた・OPEN の・クローズされた確率を 1 行ずつ表示する
+func survivalCurveLines(curve wrapper.SurvivalCurve) []string {
+	if len(curve.Points) == 0 {
+		return nil
+	}
+
+	var lines []string
+	last := curve.Points[len(curve.Points)-1]
+	for _, milestone := range survivalMilestones {
+		p := survivalPointAt(curve, milestone)
+		lines = append(lines, fmt.Sprintf(
+			"%-7s merged %3.0f%% (%.0f-%.0f%%), open %3.0f%%, closed %3.0f%%",
+			formatDuration(milestone),
+			p.Merged*100,
+			p.MergedLower*100,
+			p.MergedUpper*100,
+			p.Open*100,
+			p.Closed*100,
+		))
+		if milestone >= last.Elapsed {
+			break
+		}
+	}
+
+	return lines
+}
+
+// elapsed の時点の値。曲線は階段状なので elapsed 以前の最後の点
+func survivalPointAt(curve wrapper.SurvivalCurve, elapsed time.Duration) wrapper.SurvivalPoint {
+	point := curve.Points[0]
+	for _, p := range curve.Points {
+		if p.Elapsed > elapsed {
+			break
+		}
+		point = p
+	}
+
+	return point
+}
+
+// マージされた確率 (信頼区間つき)・OPEN の確率・クローズされた確率を階段状の折れ線で描く
+// x, y は左上の位置
+func survivalCurveSVG(curve wrapper.SurvivalCurve, x, y int) string {
+	if len(curve.Points) == 0 {
+		return ""
+	}
+
+	maxElapsed := curve.Points[len(curve.Points)-1].Elapsed
+	plotWidth := float64(survivalChartWidth - survivalAxisWidth)
+	plotHeight := float64(survivalChartHeight - survivalAxisHeight)
+	toX := func(elapsed time.Duration) float64 {
+		if maxElapsed == 0 {
+			return float64(x + survivalAxisWidth)
+		}
+		return float64(x+survivalAxisWidth) + plotWidth*float64(elapsed)/float64(maxElapsed)
+	}
+	toY := func(probability float64) float64 {
+		return float64(y) + plotHeight*(1-probability)
+	}
+	stepPoints := func(valueFunc func(p wrapper.SurvivalPoint) float64) []string {
+		var points []string
+		for i, p := range curve.Points {
+			if i > 0 {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", toX(p.Elapsed), toY(valueFunc(curve.Points[i-1]))))
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", toX(p.Elapsed), toY(valueFunc(p))))
+		}
+		return points
+	}
+
+	upper := stepPoints(func(p wrapper.SurvivalPoint) float64 { return p.MergedUpper })
+	lower := stepPoints(func(p wrapper.SurvivalPoint) float64 { return p.MergedLower })
+	for i, j := 0, len(lower)-1; i < j; i, j = i+1, j-1 {
+		lower[i], lower[j] = lower[j], lower[i]
+	}
+
+	var b strings.Builder
+	b.WriteString(`<g font-family="sans-serif" font-size="9" fill="#8b949e">`)
+	fmt.Fprintf(&b, `<text x="%d" y="%d">100%%</text>`, x, y+8)
+	fmt.Fprintf(&b, `<text x="%d" y="%.0f">0%%</text>`, x, toY(0))
+	fmt.Fprintf(&b, `<text x="%d" y="%d">0s</text>`, x+survivalAxisWidth, y+survivalChartHeight)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, x+survivalChartWidth, y+survivalChartHeight, formatDuration(maxElapsed))
+	fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="0.2"/>`, strings.Join(append(upper, lower...), " "), survivalMergedColor)
+	for _, line := range []struct {
+		color     string
+		valueFunc func(p wrapper.SurvivalPoint) float64
+	}{
+		{survivalOpenColor, func(p wrapper.SurvivalPoint) float64 { return p.Open }},
+		{survivalClosedColor, func(p wrapper.SurvivalPoint) float64 { return p.Closed }},
+		{survivalMergedColor, func(p wrapper.SurvivalPoint) float64 { return p.Merged }},
+	} {
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`, strings.Join(stepPoints(line.valueFunc), " "), line.color)
+	}
+	b.WriteString("</g>")
+
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+var svgTemplate = template.Must(template.New("card").Funcs(template.FuncMap{
+	"escape":   template.HTMLEscapeString,
+	"duration": formatDuration,
+	"add":      func(a, b int) int { return a + b },
+	"mul":      func(a, b int) int { return a * b },
+}).Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="{{ .Height }}" viewBox="0 0 {{ .Width }} {{ .Height }}">
+<style>
+.title { font: 600 18px "Segoe UI", Ubuntu, Sans-Serif; fill: #58a6ff; }
+.label { font: 400 14px "Segoe UI", Ubuntu, Sans-Serif; fill: #8b949e; }
+.value { font: 600 14px "Segoe UI", Ubuntu, Sans-Serif; fill: #e6edf3; }
+</style>
+<rect x="0.5" y="0.5" rx="4.5" width="{{ add .Width -1 }}" height="{{ add .Height -1 }}" fill="#0d1117" stroke="#30363d"/>
+<text x="25" y="35" class="title">@{{ escape .Login }}'s {{ .Year }} wrapped</text>
+{{ range $i, $line := .Lines }}<text x="25" y="{{ add 70 (mul $i 25) }}" class="label">{{ escape $line.Label }}</text>
+<text x="{{ add $.Width -25 }}" y="{{ add 70 (mul $i 25) }}" class="value" text-anchor="end">{{ escape $line.Value }}</text>
+{{ end }}{{ .Badges }}{{ .Survival }}{{ .Heatmap }}
+</svg>
+`))
+
+type svgLine struct {
+	Label string
+	Value string
+}
+
+// 主要な数値だけをまとめた README などに貼るためのカードを書き出す
+func renderSVG(w io.Writer, result *wrapper.WrappedResult) error {
+	var lines []svgLine
+	if pr := result.PullRequests; pr != nil {
+		lines = append(lines,
+			svgLine{"Pull requests opened", fmt.Sprint(pr.TotalCount)},
+			svgLine{"Pull requests merged", fmt.Sprint(pr.MergedCount)},
+		)
+		if durationStats := pr.DurationStats; durationStats != nil {
+			lines = append(lines, svgLine{"Median time to merge", formatDuration(durationStats.Percentile50)})
+		}
+		if size := pr.Size; size != nil {
+			lines = append(lines, svgLine{"Lines written / removed", fmt.Sprintf("+%d / -%d", size.TotalAdditions, size.TotalDeletions)})
+		}
+		if pr.DurationStats != nil && pr.DurationStats.BusinessHours != nil {
+			lines = append(lines, svgLine{"Median business time to merge", formatDuration(pr.DurationStats.BusinessHours.Percentile50)})
+		}
+		if survival := pr.Survival; survival != nil && survival.MedianTimeToMerge != nil {
+			lines = append(lines, svgLine{"Median time to merge (incl. open)", formatDuration(*survival.MedianTimeToMerge)})
+		}
+		if pr.LongLiveRequests != nil && len(pr.LongLiveRequests.Items) > 0 {
+			lines = append(lines, svgLine{"Longest-lived PR", formatDuration(pr.LongLiveRequests.Items[0].Duration)})
+		}
+	}
+	if reviews := result.Reviews; reviews != nil {
+		lines = append(lines, svgLine{"Reviews given", fmt.Sprint(reviews.TotalCount)})
+	}
+	if commits := result.Commits; commits != nil {
+		lines = append(lines, svgLine{"Commits", fmt.Sprint(commits.TotalCount)})
+	}
+	if issues := result.Issues; issues != nil {
+		lines = append(lines, svgLine{"Issues opened", fmt.Sprint(issues.OpenedCount)})
+	}
+
+	if calendar := result.Calendar; calendar != nil {
+		lines = append(lines,
+			svgLine{"Contributions", fmt.Sprint(calendar.TotalContributions)},
+			svgLine{"Longest streak", fmt.Sprintf("%d days", calendar.LongestStreak.Days)},
+		)
+	}
+
+	width, height := 495, 55+25*len(lines)+10
+	var badges string
+	if achievements := result.Achievements; achievements != nil && len(achievements.Earned) > 0 {
+		var badgesHeight int
+		badges, badgesHeight = badgesSVG(achievements.Earned, 25, height-10, width-50)
+		height += badgesHeight + 10
+	}
+	var survival string
+	if pr := result.PullRequests; pr != nil && pr.Survival != nil && len(pr.Survival.Curve.Points) > 1 {
+		survival = survivalCurveSVG(pr.Survival.Curve, 25, height-10)
+		height += survivalChartHeight + 10
+	}
+	var heatmap string
+	if calendar := result.Calendar; calendar != nil && len(calendar.Heatmap.Weeks) > 0 {
+		heatmapWidth, heatmapHeight := heatmapSize(calendar.Heatmap)
+		heatmap = heatmapSVG(calendar.Heatmap, 25, height-10)
+		width = max(width, heatmapWidth+50)
+		height += heatmapHeight + 10
+	}
+
+	if err := svgTemplate.Execute(w, map[string]interface{}{
+		"Login":    result.Login,
+		"Year":     result.Year,
+		"Lines":    lines,
+		"Width":    width,
+		"Height":   height,
+		"Badges":   badges,
+		"Survival": survival,
+		"Heatmap":  heatmap,
+	}); err != nil {
+		return fmt.Errorf("failed to execute svg template: %w", err)
+	}
+
+	return nil
+}
+
+const (
+	badgeHeight = 20
+	badgeGap    = 6
+	// 1 文字あたりのおおよその幅
+	badgeCharWidth = 7
+)
+
+// 獲得した実績を横に並べたバッジとして描く。maxWidth を超える場合は折り返す
+// x, y は左上の位置。描いた高さも返す
+func badgesSVG(achievements []wrapper.Achievement, x, y, maxWidth int) (string, int) {
+	var b strings.Builder
+	b.WriteString(`<g font-family="sans-serif" font-size="11">`)
+
+	offsetX, offsetY := 0, 0
+	for _, achievement := range achievements {
+		badgeWidth := len(achievement.Name)*badgeCharWidth + 16
+		if offsetX > 0 && offsetX+badgeWidth > maxWidth {
+			offsetX, offsetY = 0, offsetY+badgeHeight+badgeGap
+		}
+
+		fmt.Fprintf(
+			&b,
+			`<g><title>%s</title><rect x="%d" y="%d" width="%d" height="%d" rx="10" fill="#1f6feb" fill-opacity="0.25" stroke="#58a6ff"/><text x="%d" y="%d" fill="#e6edf3" text-anchor="middle">%s</text></g>`,
+			template.HTMLEscapeString(achievement.Description+": "+achievement.Reason),
+			x+offsetX,
+			y+offsetY,
+			badgeWidth,
+			badgeHeight,
+			x+offsetX+badgeWidth/2,
+			y+offsetY+14,
+			template.HTMLEscapeString(achievement.Name),
+		)
+		offsetX += badgeWidth + badgeGap
+	}
+	b.WriteString("</g>")
+
+	return b.String(), offsetY + badgeHeight
+}
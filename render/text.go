@@ -0,0 +1,139 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+)
+
+// 集計結果をターミナル向けのインデント付きテキストで書き出す
+func renderText(w io.Writer, result *wrapper.WrappedResult) error {
+	t := &textWriter{w: w}
+
+	t.printf(0, "@%s's %d wrapped", result.Login, result.Year)
+	for _, section := range sections(result) {
+		t.printf(0, "")
+		t.printf(0, "== %s ==", section.name)
+		t.fields(0, section.value)
+	}
+
+	return t.err
+}
+
+type textWriter struct {
+	w   io.Writer
+	err error
+	// 次に書き出す行の先頭を箇条書きの "- " にする
+	bullet bool
+}
+
+func (t *textWriter) printf(indent int, format string, args ...interface{}) {
+	if t.err != nil {
+		return
+	}
+
+	prefix := strings.Repeat("  ", indent)
+	if t.bullet && indent > 0 {
+		prefix = strings.Repeat("  ", indent-1) + "- "
+		t.bullet = false
+	}
+
+	_, t.err = fmt.Fprintf(t.w, prefix+format+"\n", args...)
+}
+
+func (t *textWriter) fields(indent int, v reflect.Value) {
+	for _, f := range visibleFields(v) {
+		t.field(indent, f.name, f.value)
+	}
+}
+
+func (t *textWriter) field(indent int, name string, v reflect.Value) {
+	if v.Type() == heatmapType {
+		t.printf(indent, "%s:", name)
+		for _, line := range heatmapLines(v.Interface().(wrapper.ContributionHeatmap)) {
+			t.printf(indent+1, "%s", line)
+		}
+		return
+	}
+
+	if v.Type() == activityHistogramType {
+		t.printf(indent, "%s:", name)
+		for _, line := range activityHistogramLines(v.Interface().(wrapper.ActivityHistogram)) {
+			t.printf(indent+1, "%s", line)
+		}
+		return
+	}
+
+	if v.Type() == survivalCurveType {
+		t.printf(indent, "%s:", name)
+		for _, line := range survivalCurveLines(v.Interface().(wrapper.SurvivalCurve)) {
+			t.printf(indent+1, "%s", line)
+		}
+		return
+	}
+
+	if s, ok := scalarString(v); ok {
+		t.printf(indent, "%s: %s", name, s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t.printf(indent, "%s:", name)
+		t.fields(indent+1, v)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			t.printf(indent, "%s: -", name)
+			return
+		}
+		if s, ok := scalarSliceString(v); ok {
+			t.printf(indent, "%s: %s", name, s)
+			return
+		}
+
+		t.printf(indent, "%s:", name)
+		for i := 0; i < v.Len(); i++ {
+			t.item(indent+1, v.Index(i))
+		}
+	case reflect.Map:
+		if v.Len() == 0 {
+			t.printf(indent, "%s: -", name)
+			return
+		}
+
+		t.printf(indent, "%s:", name)
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			value, ok := indirect(v.MapIndex(key))
+			if !ok {
+				continue
+			}
+			t.field(indent+1, fmt.Sprint(key), value)
+		}
+	}
+}
+
+func (t *textWriter) item(indent int, v reflect.Value) {
+	v, ok := indirect(v)
+	if !ok {
+		return
+	}
+
+	t.bullet = true
+	if s, ok := scalarString(v); ok {
+		t.printf(indent, "%s", s)
+		return
+	}
+
+	if v.Kind() == reflect.Struct {
+		t.fields(indent, v)
+	}
+	t.bullet = false
+}
@@ -0,0 +1,248 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kmtym1998/gh-wrapped/wrapper"
+	"github.com/volatiletech/null/v8"
+)
+
+// text / html で共通の値の整形処理
+
+var (
+	durationType          = reflect.TypeOf(time.Duration(0))
+	timeType              = reflect.TypeOf(time.Time{})
+	nullTimeType          = reflect.TypeOf(null.Time{})
+	simplePullRequestType = reflect.TypeOf(wrapper.SimplePullRequest{})
+	simpleIssueType       = reflect.TypeOf(wrapper.SimpleIssue{})
+	metricDeltaType       = reflect.TypeOf(wrapper.MetricDelta{})
+	trendPointType        = reflect.TypeOf(wrapper.PullRequestTrendPoint{})
+)
+
+// 頭字語はそのまま残す
+var acronyms = map[string]bool{"URL": true, "OSS": true, "PR": true, "PRS": true, "ID": true}
+
+// CamelCase のフィールド名を "Camel case" のような表示名にする
+func humanize(name string) string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) ||
+			(unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1])) ||
+			(unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) ||
+			(unicode.IsDigit(runes[i]) && !unicode.IsDigit(runes[i-1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	for i, word := range words {
+		if acronyms[strings.ToUpper(word)] && strings.ToUpper(word) == word {
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		words[i] = strings.ToLower(word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// 1d 2h のように大きい単位から 2 つだけ表示する
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	var parts []string
+	for _, u := range units {
+		if d >= u.unit || len(parts) > 0 {
+			parts = append(parts, strconv.FormatInt(int64(d/u.unit), 10)+u.suffix)
+			d %= u.unit
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return sign + d.String()
+	}
+
+	return sign + strings.Join(parts, " ")
+}
+
+func formatSimplePullRequest(pr wrapper.SimplePullRequest) string {
+	return fmt.Sprintf("%s/%s#%d %s", pr.Owner, pr.Repo, pr.Number, pr.Title)
+}
+
+func formatSimpleIssue(issue wrapper.SimpleIssue) string {
+	return fmt.Sprintf("%s/%s#%d %s", issue.Owner, issue.Repo, issue.Number, issue.Title)
+}
+
+// "Duration stats / percentile50: 1d 2h → 20h (-6h, -23%)" のように 1 行にする
+func formatMetricDelta(delta wrapper.MetricDelta) string {
+	format := func(v float64) string {
+		switch delta.Unit {
+		case wrapper.MetricUnitDuration:
+			return formatDuration(time.Duration(v))
+		case wrapper.MetricUnitRatio:
+			return strconv.FormatFloat(v, 'f', 2, 64)
+		default:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+
+	names := strings.Split(delta.Metric, ".")
+	for i, name := range names {
+		names[i] = humanize(name)
+	}
+
+	change := format(delta.Difference)
+	if delta.Difference >= 0 {
+		change = "+" + change
+	}
+	if delta.ChangeRate != nil {
+		change += fmt.Sprintf(", %+.0f%%", *delta.ChangeRate*100)
+	}
+
+	return fmt.Sprintf("%s: %s → %s (%s)", strings.Join(names, " / "), format(delta.Previous), format(delta.Current), change)
+}
+
+func formatTrendPoint(point wrapper.PullRequestTrendPoint) string {
+	return fmt.Sprintf(
+		"%s: %d opened, %d merged (%.0f%%), median lifetime %s",
+		point.Period,
+		point.OpenedCount,
+		point.MergedCount,
+		point.MergeRate*100,
+		formatDuration(point.MedianLifetime),
+	)
+}
+
+// ポインタとインターフェースを剥がす。nil の場合は false
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+
+	return v, v.IsValid()
+}
+
+// 1 行で表示できる値の場合は文字列にして true を返す
+func scalarString(v reflect.Value) (string, bool) {
+	switch v.Type() {
+	case durationType:
+		return formatDuration(time.Duration(v.Int())), true
+	case timeType:
+		return v.Interface().(time.Time).Format("2006-01-02 15:04"), true
+	case nullTimeType:
+		t := v.Interface().(null.Time)
+		if !t.Valid {
+			return "-", true
+		}
+		return t.Time.Format("2006-01-02 15:04"), true
+	case simplePullRequestType:
+		return formatSimplePullRequest(v.Interface().(wrapper.SimplePullRequest)), true
+	case simpleIssueType:
+		return formatSimpleIssue(v.Interface().(wrapper.SimpleIssue)), true
+	case metricDeltaType:
+		return formatMetricDelta(v.Interface().(wrapper.MetricDelta)), true
+	case trendPointType:
+		return formatTrendPoint(v.Interface().(wrapper.PullRequestTrendPoint)), true
+	case collaborationGraphType:
+		return formatCollaborationGraph(v.Interface().(wrapper.CollaborationGraph)), true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return "-", true
+		}
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', 2, 64), true
+	}
+
+	return "", false
+}
+
+// スライスの要素がすべて 1 行で表示できる場合はカンマ区切りにする
+// 1 行でも長い要素は 1 件ずつ表示する
+func scalarSliceString(v reflect.Value) (string, bool) {
+	if elem := v.Type().Elem(); elem == metricDeltaType || elem == trendPointType {
+		return "", false
+	}
+
+	var values []string
+	for i := 0; i < v.Len(); i++ {
+		elem, ok := indirect(v.Index(i))
+		if !ok {
+			continue
+		}
+
+		s, ok := scalarString(elem)
+		if !ok {
+			return "", false
+		}
+		values = append(values, s)
+	}
+
+	return strings.Join(values, ", "), true
+}
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// 表示する構造体のフィールドを返す。非公開フィールドと nil は除く
+func visibleFields(v reflect.Value) []field {
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		value, ok := indirect(v.Field(i))
+		if !ok {
+			continue
+		}
+
+		fields = append(fields, field{name: humanize(f.Name), value: value})
+	}
+
+	return fields
+}
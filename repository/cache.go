@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+const cacheTimeFormat = "20060102T150405Z0700"
+
+// クエリを変えずに model への変換だけを変えた場合に上げる。上げるとすべてのクエリのキャッシュが無効になる
+const cacheVersion = "1"
+
+// GitHubRepository の結果をファイルにキャッシュする
+type CachedGitHubRepository struct {
+	repo GitHubRepository
+	dir  string
+	ttl  time.Duration
+}
+
+var _ GitHubRepository = (*CachedGitHubRepository)(nil)
+
+// dir 配下に ttl の間キャッシュする。dir はホストやアカウントごとに分けておくこと
+func NewCachedGitHub(repo GitHubRepository, dir string, ttl time.Duration) *CachedGitHubRepository {
+	return &CachedGitHubRepository{
+		repo: repo,
+		dir:  dir,
+		ttl:  ttl,
+	}
+}
+
+func (r *CachedGitHubRepository) ListOrganizations() ([]*Organization, error) {
+	return cached(r, "organizations", r.repo.ListOrganizations)
+}
+
+func (r *CachedGitHubRepository) ListPullRequests(from, to time.Time, fields PullRequestFields) ([]*PullRequest, error) {
+	return cached(r, cacheKey("pull_requests", wrapPullRequestQuery, from, to)+fields.cacheKeySuffix(), func() ([]*PullRequest, error) {
+		return r.repo.ListPullRequests(from, to, fields)
+	})
+}
+
+func (r *CachedGitHubRepository) ListOpenPullRequests(fields PullRequestFields) ([]*PullRequest, error) {
+	return cached(r, "open_pull_requests_"+queryHash(wrapOpenPullRequestQuery)+fields.cacheKeySuffix(), func() ([]*PullRequest, error) {
+		return r.repo.ListOpenPullRequests(fields)
+	})
+}
+
+func (r *CachedGitHubRepository) ListGivenReviews(from, to time.Time) ([]*GivenReview, error) {
+	return cached(r, cacheKey("given_reviews", wrapReviewQuery, from, to), func() ([]*GivenReview, error) {
+		return r.repo.ListGivenReviews(from, to)
+	})
+}
+
+func (r *CachedGitHubRepository) ListIssues(from, to time.Time) ([]*Issue, error) {
+	return cached(r, cacheKey("issues", wrapIssueQuery, from, to), func() ([]*Issue, error) {
+		return r.repo.ListIssues(from, to)
+	})
+}
+
+func (r *CachedGitHubRepository) ListCommitContributions(from, to time.Time) (*CommitContributions, error) {
+	return cached(r, cacheKey("commit_contributions", wrapCommitQuery, from, to), func() (*CommitContributions, error) {
+		return r.repo.ListCommitContributions(from, to)
+	})
+}
+
+func (r *CachedGitHubRepository) GetContributionCalendar(from, to time.Time) (*ContributionCalendar, error) {
+	return cached(r, cacheKey("contribution_calendar", wrapCalendarQuery, from, to), func() (*ContributionCalendar, error) {
+		return r.repo.GetContributionCalendar(from, to)
+	})
+}
+
+func (r *CachedGitHubRepository) GetMe() (*PublicUser, error) {
+	return cached(r, "me", r.repo.GetMe)
+}
+
+// ホストと認証トークンごとのキャッシュディレクトリを返す
+// gh auth switch などでアカウントを切り替えたときに別のユーザーのキャッシュを使わないようにトークンのハッシュで分ける
+func CacheDirFor(baseDir, host string) string {
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
+
+	token, _ := auth.TokenForHost(host)
+	sum := sha256.Sum256([]byte(token))
+
+	return filepath.Join(baseDir, host, hex.EncodeToString(sum[:])[:12])
+}
+
+// クエリで取得する項目を変えたときに古いキャッシュを使わないよう、キーにクエリのハッシュを含める
+func cacheKey(name, query string, from, to time.Time) string {
+	return name + "_" + queryHash(query) + "_" + from.Format(cacheTimeFormat) + "_" + to.Format(cacheTimeFormat)
+}
+
+// 取得した項目ごとに別のキャッシュにする
+func (f PullRequestFields) cacheKeySuffix() string {
+	return fmt.Sprintf("_reviews-%t_comments-%t_timeline-%t", f.Reviews, f.Reviews && f.ReviewComments, f.TimelineEvents)
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(cacheVersion + query))
+
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// キャッシュが有効ならそれを返し、なければ fetch した結果をキャッシュに書き込んで返す
+// キャッシュの読み書きに失敗しても fetch の結果を返す
+func cached[T any](r *CachedGitHubRepository, key string, fetch func() (T, error)) (T, error) {
+	path := filepath.Join(r.dir, key+".json")
+
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < r.ttl {
+		b, err := os.ReadFile(path)
+		if err == nil {
+			var v T
+			if err = json.Unmarshal(b, &v); err == nil {
+				slog.Debug("cache hit", "key", key)
+				return v, nil
+			}
+		}
+
+		slog.Warn("failed to read cache", "key", key, "error", err)
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("failed to encode cache", "key", key, "error", err)
+		return v, nil
+	}
+
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		slog.Warn("failed to create cache directory", "dir", r.dir, "error", err)
+		return v, nil
+	}
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		slog.Warn("failed to write cache", "key", key, "error", err)
+	}
+
+	return v, nil
+}
+
+type CacheEntry struct {
+	// キャッシュディレクトリからの相対パス
+	Path      string
+	Size      int64
+	UpdatedAt time.Time
+}
+
+// dir 配下のキャッシュファイルを列挙する。dir が存在しない場合は空を返す
+func ListCacheEntries(dir string) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, CacheEntry{
+			Path:      rel,
+			Size:      info.Size(),
+			UpdatedAt: info.ModTime(),
+		})
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk cache directory: %w", err)
+	}
+
+	return entries, nil
+}
+
+// dir 配下のキャッシュをすべて削除する
+func ClearCache(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove cache directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// from ~ to のコントリビューションカレンダーを返す
+func (r *GitHubClient) GetContributionCalendar(from, to time.Time) (*ContributionCalendar, error) {
+	var response WrapCalendarResponse
+
+	variables := map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+
+	slog.Debug(
+		"getting contribution calendar...",
+		"variables", variables,
+	)
+
+	if err := r.graphQLClient.Do(
+		wrapCalendarQuery,
+		variables,
+		&response,
+	); err != nil {
+		return nil, err
+	}
+
+	calendar := response.Viewer.ContributionsCollection.ContributionCalendar
+	result := &ContributionCalendar{
+		TotalContributions: calendar.TotalContributions,
+	}
+	for _, week := range calendar.Weeks {
+		for _, day := range week.ContributionDays {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse contribution date: %w", err)
+			}
+
+			result.Days = append(result.Days, ContributionDay{
+				Date:    date,
+				Weekday: time.Weekday(day.Weekday),
+				Count:   day.ContributionCount,
+			})
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+)
+
+// 1 回のクエリで取得する期間
+// contributions は 1 日 1 件で first: 100 までしか取れないため、100 日未満に区切って取得する
+const commitContributionsWindow = 90 * 24 * time.Hour
+
+// from ~ to の間のコミットの contribution を返す
+func (r *GitHubClient) ListCommitContributions(from, to time.Time) (*CommitContributions, error) {
+	result := &CommitContributions{}
+	for windowFrom := from; windowFrom.Before(to); windowFrom = windowFrom.Add(commitContributionsWindow) {
+		windowTo := windowFrom.Add(commitContributionsWindow - time.Second)
+		if windowTo.After(to) {
+			windowTo = to
+		}
+
+		var response WrapCommitsResponse
+
+		variables := map[string]interface{}{
+			"from":                    windowFrom,
+			"to":                      windowTo,
+			"commitRepositoriesLimit": commitRepositoriesLimit,
+		}
+
+		slog.Debug(
+			"getting commit contributions...",
+			"variables", variables,
+		)
+
+		if err := r.graphQLClient.Do(
+			wrapCommitQuery,
+			variables,
+			&response,
+		); err != nil {
+			return nil, err
+		}
+
+		collection := response.Viewer.ContributionsCollection
+		result.TotalCount += collection.TotalCommitContributions
+
+		for _, node := range collection.CommitContributionsByRepository {
+			for _, contribution := range node.Contributions.Nodes {
+				result.Contributions = append(result.Contributions, CommitContribution{
+					RepositoryOwner: node.Repository.Owner.Login,
+					RepositoryName:  node.Repository.Name,
+					OccurredAt:      contribution.OccurredAt,
+					CommitCount:     contribution.CommitCount,
+				})
+			}
+		}
+
+		// レートリミット対策のために sleep
+		time.Sleep(1 * time.Second)
+	}
+
+	slog.Debug("got commit contributions", "total", result.TotalCount, "len", len(result.Contributions))
+
+	return result, nil
+}
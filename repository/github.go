@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/samber/lo"
 )
 
 type GitHubClient struct {
@@ -21,15 +25,24 @@ type GitHubClient struct {
 
 type GitHubRepository interface {
 	ListOrganizations() ([]*Organization, error)
-	ListPullRequests(from, to time.Time) ([]*PullRequest, error)
+	ListPullRequests(from, to time.Time, fields PullRequestFields) ([]*PullRequest, error)
+	ListOpenPullRequests(fields PullRequestFields) ([]*PullRequest, error)
+	ListGivenReviews(from, to time.Time) ([]*GivenReview, error)
+	ListIssues(from, to time.Time) ([]*Issue, error)
+	ListCommitContributions(from, to time.Time) (*CommitContributions, error)
+	GetContributionCalendar(from, to time.Time) (*ContributionCalendar, error)
 	GetMe() (*PublicUser, error)
 }
 
-func NewGitHub() (*GitHubClient, error) {
-	host, _ := auth.DefaultHost()
+// host が空の場合は gh の設定に従う
+func NewGitHub(host string) (*GitHubClient, error) {
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
 
 	rest, err := api.NewRESTClient(
 		api.ClientOptions{
+			Host:    host,
 			Timeout: 10 * time.Second,
 		})
 	if err != nil {
@@ -38,6 +51,7 @@ func NewGitHub() (*GitHubClient, error) {
 
 	graphql, err := api.NewGraphQLClient(
 		api.ClientOptions{
+			Host:    host,
 			Timeout: 10 * time.Second,
 		})
 	if err != nil {
@@ -67,17 +81,41 @@ type Organization struct {
 }
 
 // https://docs.github.com/ja/rest/orgs/orgs?apiVersion=2022-11-28#list-organizations
+// 100 件を超える場合は Link ヘッダーの next をたどってすべて取得する
 func (r *GitHubClient) ListOrganizations() ([]*Organization, error) {
-	var response []*Organization
-	err := r.restClient.Get("user/orgs", &response)
-	if err != nil {
-		return nil, err
+	var organizations []*Organization
+	for path := "user/orgs?per_page=100"; path != ""; {
+		response, err := r.restClient.Request(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []*Organization
+		err = json.NewDecoder(response.Body).Decode(&page)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode organizations: %w", err)
+		}
+		organizations = append(organizations, page...)
+
+		path = nextPageURL(response.Header.Get("Link"))
 	}
 
-	return response, nil
+	return organizations, nil
 }
 
-func (r *GitHubClient) ListPullRequests(from, to time.Time) ([]*PullRequest, error) {
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Link ヘッダーから次のページの URL を取り出す。最後のページの場合は空
+func nextPageURL(link string) string {
+	if match := linkNextPattern.FindStringSubmatch(link); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func (r *GitHubClient) ListPullRequests(from, to time.Time, fields PullRequestFields) ([]*PullRequest, error) {
 	var nextCursor string
 	var pullRequests []*PullRequest
 	// TODO: プログレスの表示
@@ -89,6 +127,11 @@ func (r *GitHubClient) ListPullRequests(from, to time.Time) ([]*PullRequest, err
 			"to":                  to,
 			"reviewsLimit":        reviewsLimit,
 			"reviewCommentsLimit": reviewCommentsLimit,
+			"labelsLimit":         labelsLimit,
+			"timelineItemsLimit":  timelineItemsLimit,
+		}
+		for k, v := range fields.variables() {
+			variables[k] = v
 		}
 
 		if nextCursor != "" {
@@ -115,57 +158,7 @@ func (r *GitHubClient) ListPullRequests(from, to time.Time) ([]*PullRequest, err
 		}
 
 		for _, node := range response.Viewer.ContributionsCollection.PullRequestContributions.Nodes {
-			// TODO: review と comment のページング
-
-			pullRequests = append(pullRequests, &PullRequest{
-				ID:              node.PullRequest.ID,
-				Number:          node.PullRequest.Number,
-				Title:           node.PullRequest.Title,
-				RepositoryOwner: node.PullRequest.Repository.Owner.Login,
-				RepositoryName:  node.PullRequest.Repository.Name,
-				CreatedAt:       node.PullRequest.CreatedAt,
-				ClosedAt:        node.PullRequest.ClosedAt,
-				MergedAt:        node.PullRequest.MergedAt,
-				State:           FromString(node.PullRequest.State),
-				CommitsCount:    node.PullRequest.Commits.TotalCount,
-				CommentsCount:   node.PullRequest.CommentsCount(),
-				// NOTE: struct の定義がめんどくさくて lo.Map を使ってない
-				Reviews: func() []PullRequestReview {
-					var reviews []PullRequestReview
-					for _, review := range node.PullRequest.Reviews.Nodes {
-						var comments []PullRequestComment
-						for _, comment := range review.Comments.Nodes {
-							comments = append(comments, PullRequestComment{
-								ID:     comment.ID,
-								Author: comment.Author.Login,
-								ReplyTo: func() string {
-									// NOTE: なんかこれだと動かなかった。nil ぽが起きる
-									// lo.Ternary(
-									// 	comment.ReplyTo != nil,
-									// 	comment.ReplyTo.ID,
-									// 	"",
-									// ),
-									if comment.ReplyTo != nil {
-										return comment.ReplyTo.ID
-									}
-
-									return ""
-								}(),
-							})
-						}
-
-						reviews = append(reviews, PullRequestReview{
-							ID:       review.ID,
-							Author:   review.Author.Login,
-							State:    review.State,
-							Comments: comments,
-						})
-					}
-
-					return reviews
-				}(),
-				URL: "https://" + r.host + "/" + node.PullRequest.Repository.Owner.Login + "/" + node.PullRequest.Repository.Name + "/pull/" + strconv.Itoa(node.PullRequest.Number),
-			})
+			pullRequests = append(pullRequests, r.toPullRequest(node.PullRequest))
 		}
 
 		if !response.Viewer.ContributionsCollection.PullRequestContributions.PageInfo.HasNextPage {
@@ -190,3 +183,142 @@ func (r *GitHubClient) ListPullRequests(from, to time.Time) ([]*PullRequest, err
 
 	return pullRequests, nil
 }
+
+// 作成した年によらず、OPEN のままの自分の PR を取得する
+func (r *GitHubClient) ListOpenPullRequests(fields PullRequestFields) ([]*PullRequest, error) {
+	var nextCursor string
+	var pullRequests []*PullRequest
+	for {
+		var response WrapOpenPullRequestsResponse
+
+		variables := map[string]interface{}{
+			"reviewsLimit":        reviewsLimit,
+			"reviewCommentsLimit": reviewCommentsLimit,
+			"labelsLimit":         labelsLimit,
+			"timelineItemsLimit":  timelineItemsLimit,
+		}
+		for k, v := range fields.variables() {
+			variables[k] = v
+		}
+
+		if nextCursor != "" {
+			variables["prAfterCursor"] = nextCursor
+		}
+
+		slog.Debug(
+			"getting open pull requests...",
+			"variables", variables,
+		)
+
+		if err := r.graphQLClient.Do(
+			wrapOpenPullRequestQuery,
+			variables,
+			&response,
+		); err != nil {
+			return nil, err
+		}
+
+		for _, node := range response.Viewer.PullRequests.Nodes {
+			pullRequests = append(pullRequests, r.toPullRequest(node))
+		}
+
+		if !response.Viewer.PullRequests.PageInfo.HasNextPage {
+			break
+		}
+
+		nextCursor = response.Viewer.PullRequests.PageInfo.EndCursor
+
+		// レートリミット対策のために sleep
+		time.Sleep(1 * time.Second)
+	}
+
+	return pullRequests, nil
+}
+
+// GraphQL のレスポンスを PullRequest に変換する
+func (r *GitHubClient) toPullRequest(node PullRequestNode) *PullRequest {
+	// TODO: review と comment のページング
+	return &PullRequest{
+		ID:               node.ID,
+		Number:           node.Number,
+		Title:            node.Title,
+		RepositoryOwner:  node.Repository.Owner.Login,
+		RepositoryName:   node.Repository.Name,
+		RepositoryIsFork: node.Repository.IsFork,
+		IsDraft:          node.IsDraft,
+		Labels: lo.Map(node.Labels.Nodes, func(label LabelNode, _ int) string {
+			return label.Name
+		}),
+		CreatedAt: node.CreatedAt,
+		UpdatedAt: node.UpdatedAt,
+		ClosedAt:  node.ClosedAt,
+		MergedAt:  node.MergedAt,
+		MergedBy: func() string {
+			if node.MergedBy == nil {
+				return ""
+			}
+
+			return node.MergedBy.Login
+		}(),
+		MergeMethod:   node.MergeMethod(),
+		State:         FromString(node.State),
+		CommitsCount:  node.Commits.TotalCount,
+		CommentsCount: node.CommentsCount(),
+		Additions:     node.Additions,
+		Deletions:     node.Deletions,
+		ChangedFiles:  node.ChangedFiles,
+		// NOTE: struct の定義がめんどくさくて lo.Map を使ってない
+		Reviews: func() []PullRequestReview {
+			var reviews []PullRequestReview
+			for _, review := range node.Reviews.Nodes {
+				var comments []PullRequestComment
+				for _, comment := range review.Comments.Nodes {
+					comments = append(comments, PullRequestComment{
+						ID:          comment.ID,
+						Author:      comment.Author.Login,
+						AuthorIsBot: comment.Author.IsBot(),
+						ReplyTo: func() string {
+							// NOTE: なんかこれだと動かなかった。nil ぽが起きる
+							// lo.Ternary(
+							// 	comment.ReplyTo != nil,
+							// 	comment.ReplyTo.ID,
+							// 	"",
+							// ),
+							if comment.ReplyTo != nil {
+								return comment.ReplyTo.ID
+							}
+
+							return ""
+						}(),
+					})
+				}
+
+				reviews = append(reviews, PullRequestReview{
+					ID:          review.ID,
+					Author:      review.Author.Login,
+					AuthorIsBot: review.Author.IsBot(),
+					State:       review.State,
+					SubmittedAt: review.SubmittedAt,
+					Comments:    comments,
+				})
+			}
+
+			return reviews
+		}(),
+		TimelineEvents: lo.Map(node.TimelineItems.Nodes, func(item TimelineItemNode, _ int) PullRequestTimelineEvent {
+			event := PullRequestTimelineEvent{
+				Type:      item.Typename,
+				CreatedAt: item.CreatedAt,
+			}
+			if item.Actor != nil {
+				event.Actor = item.Actor.Login
+			}
+			if item.StateReason != nil {
+				event.StateReason = *item.StateReason
+			}
+
+			return event
+		}),
+		URL: "https://" + r.host + "/" + node.Repository.Owner.Login + "/" + node.Repository.Name + "/pull/" + strconv.Itoa(node.Number),
+	}
+}
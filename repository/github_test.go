@@ -0,0 +1,40 @@
+package repository
+
+import "testing"
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "first page",
+			link: `<https://api.github.com/user/orgs?per_page=100&page=2>; rel="next", <https://api.github.com/user/orgs?per_page=100&page=3>; rel="last"`,
+			want: "https://api.github.com/user/orgs?per_page=100&page=2",
+		},
+		{
+			name: "middle page",
+			link: `<https://api.github.com/user/orgs?per_page=100&page=1>; rel="prev", <https://api.github.com/user/orgs?per_page=100&page=3>; rel="next"`,
+			want: "https://api.github.com/user/orgs?per_page=100&page=3",
+		},
+		{
+			name: "last page",
+			link: `<https://api.github.com/user/orgs?per_page=100&page=2>; rel="prev", <https://api.github.com/user/orgs?per_page=100&page=1>; rel="first"`,
+			want: "",
+		},
+		{
+			name: "single page",
+			link: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+const wrapCalendarQuery = `
+query WrapCalendar($from: DateTime, $to: DateTime) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      contributionCalendar {
+        totalContributions
+        weeks {
+          contributionDays {
+            date
+            weekday
+            contributionCount
+          }
+        }
+      }
+    }
+  }
+}`
+
+type WrapCalendarResponse struct {
+	Viewer struct {
+		ContributionsCollection struct {
+			ContributionCalendar struct {
+				TotalContributions int `json:"totalContributions"`
+				Weeks              []struct {
+					ContributionDays []ContributionDayNode `json:"contributionDays"`
+				} `json:"weeks"`
+			} `json:"contributionCalendar"`
+		} `json:"contributionsCollection"`
+	} `json:"viewer"`
+}
+
+type ContributionDayNode struct {
+	// 2006-01-02 形式
+	Date              string `json:"date"`
+	Weekday           int    `json:"weekday"`
+	ContributionCount int    `json:"contributionCount"`
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+)
+
+// commitContributionsByRepository で取得できるリポジトリの上限
+const commitRepositoriesLimit = 100
+
+const wrapCommitQuery = `
+query WrapCommit($from: DateTime, $to: DateTime, $commitRepositoriesLimit: Int = 100) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      totalCommitContributions
+      commitContributionsByRepository(maxRepositories: $commitRepositoriesLimit) {
+        repository {
+          owner {
+            login
+          }
+          name
+        }
+        contributions(first: 100) {
+          totalCount
+          nodes {
+            occurredAt
+            commitCount
+          }
+        }
+      }
+    }
+  }
+}`
+
+type WrapCommitsResponse struct {
+	Viewer struct {
+		ContributionsCollection struct {
+			TotalCommitContributions        int                             `json:"totalCommitContributions"`
+			CommitContributionsByRepository []CommitContributionsByRepoNode `json:"commitContributionsByRepository"`
+		} `json:"contributionsCollection"`
+	} `json:"viewer"`
+}
+
+type CommitContributionsByRepoNode struct {
+	Repository struct {
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+		Name string `json:"name"`
+	} `json:"repository"`
+	Contributions struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			OccurredAt  time.Time `json:"occurredAt"`
+			CommitCount int       `json:"commitCount"`
+		} `json:"nodes"`
+	} `json:"contributions"`
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+)
+
+const wrapIssueQuery = `
+query WrapIssue($from: DateTime, $to: DateTime, $issueAfterCursor: String, $labelsLimit: Int = 20) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      issueContributions(first: 100, after: $issueAfterCursor) {
+        totalCount
+        pageInfo {
+          endCursor
+          hasNextPage
+        }
+        nodes {
+          issue {
+            id
+            number
+            title
+            url
+            state
+            createdAt
+            closedAt
+            comments {
+              totalCount
+            }
+            labels(first: $labelsLimit) {
+              nodes {
+                name
+              }
+            }
+            repository {
+              owner {
+                login
+              }
+              name
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type WrapIssuesResponse struct {
+	Viewer struct {
+		ContributionsCollection struct {
+			IssueContributions struct {
+				TotalCount int      `json:"totalCount"`
+				PageInfo   PageInfo `json:"pageInfo"`
+				Nodes      []struct {
+					Issue IssueNode `json:"issue"`
+				} `json:"nodes"`
+			} `json:"issueContributions"`
+		} `json:"contributionsCollection"`
+	} `json:"viewer"`
+}
+
+type IssueNode struct {
+	ID        string    `json:"id"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	ClosedAt  null.Time `json:"closedAt"`
+	Comments  struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Labels struct {
+		Nodes []LabelNode `json:"nodes"`
+	} `json:"labels"`
+	Repository struct {
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+		Name string `json:"name"`
+	} `json:"repository"`
+}
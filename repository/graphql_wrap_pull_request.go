@@ -3,16 +3,118 @@ package repository
 import (
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/volatiletech/null/v8"
 )
 
 const (
 	reviewsLimit        = 50
 	reviewCommentsLimit = 50
+	labelsLimit         = 20
+	timelineItemsLimit  = 50
 )
 
+// 集計に使う PR の項目。PR を取得するクエリで共通して使う
+// レビュー・レビューコメント・タイムラインのイベントは $with〜 が false の場合は取得しない
+const pullRequestFieldsFragment = `
+fragment WrapPullRequestFields on PullRequest {
+  id
+  number
+  title
+  repository {
+    owner {
+      id
+      login
+    }
+    name
+    isFork
+  }
+  isDraft
+  labels(first: $labelsLimit) {
+    nodes {
+      name
+    }
+  }
+  commits {
+    totalCount
+  }
+  additions
+  deletions
+  changedFiles
+  state
+  createdAt
+  updatedAt
+  closedAt
+  mergedAt
+  mergedBy {
+    __typename
+    login
+  }
+  mergeCommit {
+    additions
+    deletions
+    parents {
+      totalCount
+    }
+  }
+  reviews(first: $reviewsLimit) @include(if: $withReviews) {
+    totalCount
+    pageInfo {
+      endCursor
+      hasNextPage
+    }
+    nodes {
+      id
+      state
+      submittedAt
+      author {
+        __typename
+        login
+      }
+      comments(first: $reviewCommentsLimit) @include(if: $withReviewComments) {
+        pageInfo {
+          endCursor
+          hasNextPage
+        }
+        nodes {
+          id
+          replyTo {
+            id
+          }
+          author {
+            __typename
+            login
+          }
+        }
+      }
+    }
+  }
+  timelineItems(first: $timelineItemsLimit, itemTypes: [READY_FOR_REVIEW_EVENT, CONVERT_TO_DRAFT_EVENT, REVIEW_REQUESTED_EVENT, CLOSED_EVENT]) @include(if: $withTimelineItems) {
+    nodes {
+      __typename
+      ... on ReadyForReviewEvent {
+        createdAt
+      }
+      ... on ConvertToDraftEvent {
+        createdAt
+      }
+      ... on ReviewRequestedEvent {
+        createdAt
+      }
+      ... on ClosedEvent {
+        createdAt
+        stateReason
+        actor {
+          __typename
+          login
+        }
+      }
+    }
+  }
+}`
+
 const wrapPullRequestQuery = `
-query WrapPullRequest($from: DateTime, $to: DateTime, $prAfterCursor: String, $reviewsLimit: Int = 50, $reviewCommentsLimit: Int = 50) {
+query WrapPullRequest($from: DateTime, $to: DateTime, $prAfterCursor: String, $reviewsLimit: Int = 50, $reviewCommentsLimit: Int = 50, $labelsLimit: Int = 20, $timelineItemsLimit: Int = 50, $withReviews: Boolean = true, $withReviewComments: Boolean = true, $withTimelineItems: Boolean = true) {
   viewer {
     contributionsCollection(from: $from, to: $to) {
       pullRequestContributions(first: 100, after: $prAfterCursor) {
@@ -23,58 +125,51 @@ query WrapPullRequest($from: DateTime, $to: DateTime, $prAfterCursor: String, $r
         }
         nodes {
           pullRequest {
-            id
-            number
-            title
-            repository {
-              owner {
-                id
-                login
-              }
-              name
-            }
-            commits {
-              totalCount
-            }
-            state
-            createdAt
-            closedAt
-            mergedAt
-            reviews(first: $reviewsLimit) {
-              totalCount
-              pageInfo {
-                endCursor
-                hasNextPage
-              }
-              nodes {
-                id
-                state
-                author {
-                  login
-                }
-                comments(first: $reviewCommentsLimit) {
-                  pageInfo {
-                    endCursor
-                    hasNextPage
-                  }
-                  nodes {
-                    id
-                    replyTo {
-                      id
-                    }
-                    author {
-                      login
-                    }
-                  }
-                }
-              }
-            }
+            ...WrapPullRequestFields
           }
         }
       }
     }
   }
-}`
+}` + pullRequestFieldsFragment
+
+// 作成した年によらず、OPEN のままの PR を取得する
+const wrapOpenPullRequestQuery = `
+query WrapOpenPullRequest($prAfterCursor: String, $reviewsLimit: Int = 50, $reviewCommentsLimit: Int = 50, $labelsLimit: Int = 20, $timelineItemsLimit: Int = 50, $withReviews: Boolean = true, $withReviewComments: Boolean = true, $withTimelineItems: Boolean = true) {
+  viewer {
+    pullRequests(first: 100, after: $prAfterCursor, states: [OPEN]) {
+      totalCount
+      pageInfo {
+        endCursor
+        hasNextPage
+      }
+      nodes {
+        ...WrapPullRequestFields
+      }
+    }
+  }
+}` + pullRequestFieldsFragment
+
+// PR と一緒に取得する項目。false の項目は取得せず、PullRequest では空のままになる
+type PullRequestFields struct {
+	Reviews bool
+	// Reviews が false の場合は取得しない。false の場合 PullRequest.CommentsCount は 0 になる
+	ReviewComments bool
+	TimelineEvents bool
+}
+
+// すべての項目を取得する
+func AllPullRequestFields() PullRequestFields {
+	return PullRequestFields{Reviews: true, ReviewComments: true, TimelineEvents: true}
+}
+
+func (f PullRequestFields) variables() map[string]interface{} {
+	return map[string]interface{}{
+		"withReviews":        f.Reviews,
+		"withReviewComments": f.Reviews && f.ReviewComments,
+		"withTimelineItems":  f.TimelineEvents,
+	}
+}
 
 type WrapPullRequestsResponse struct {
 	Viewer struct {
@@ -90,6 +185,16 @@ type WrapPullRequestsResponse struct {
 	} `json:"viewer"`
 }
 
+type WrapOpenPullRequestsResponse struct {
+	Viewer struct {
+		PullRequests struct {
+			TotalCount int               `json:"totalCount"`
+			PageInfo   PageInfo          `json:"pageInfo"`
+			Nodes      []PullRequestNode `json:"nodes"`
+		} `json:"pullRequests"`
+	} `json:"viewer"`
+}
+
 type PullRequestNode struct {
 	ID         string `json:"id"`
 	Number     int    `json:"number"`
@@ -99,29 +204,62 @@ type PullRequestNode struct {
 			ID    string `json:"id"`
 			Login string `json:"login"`
 		} `json:"owner"`
-		Name string `json:"name"`
+		Name   string `json:"name"`
+		IsFork bool   `json:"isFork"`
 	} `json:"repository"`
+	IsDraft bool `json:"isDraft"`
+	Labels  struct {
+		Nodes []LabelNode `json:"nodes"`
+	} `json:"labels"`
 	Commits struct {
 		TotalCount int `json:"totalCount"`
 	} `json:"commits"`
-	State     string    `json:"state"`
-	CreatedAt time.Time `json:"createdAt"`
-	ClosedAt  null.Time `json:"closedAt"`
-	MergedAt  null.Time `json:"mergedAt"`
-	Reviews   struct {
+	Additions    int       `json:"additions"`
+	Deletions    int       `json:"deletions"`
+	ChangedFiles int       `json:"changedFiles"`
+	State        string    `json:"state"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	ClosedAt     null.Time `json:"closedAt"`
+	MergedAt     null.Time `json:"mergedAt"`
+	// マージされていない PR と削除されたユーザーがマージした PR は nil
+	MergedBy *ActorNode `json:"mergedBy"`
+	// マージされていない PR は nil
+	MergeCommit *struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+		Parents   struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"parents"`
+	} `json:"mergeCommit"`
+	Reviews struct {
 		TotalCount int          `json:"totalCount"`
 		PageInfo   PageInfo     `json:"pageInfo"`
 		Nodes      []ReviewNode `json:"nodes"`
 	} `json:"reviews"`
+	TimelineItems struct {
+		Nodes []TimelineItemNode `json:"nodes"`
+	} `json:"timelineItems"`
+}
+
+type TimelineItemNode struct {
+	Typename  string    `json:"__typename"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ClosedEvent のみ
+	StateReason *string    `json:"stateReason"`
+	Actor       *ActorNode `json:"actor"`
+}
+
+type LabelNode struct {
+	Name string `json:"name"`
 }
 
 type ReviewNode struct {
-	ID     string `json:"id"`
-	State  string `json:"state"`
-	Author struct {
-		Login string `json:"login"`
-	} `json:"author"`
-	Comments struct {
+	ID          string    `json:"id"`
+	State       string    `json:"state"`
+	SubmittedAt null.Time `json:"submittedAt"`
+	Author      ActorNode `json:"author"`
+	Comments    struct {
 		PageInfo PageInfo            `json:"pageInfo"`
 		Nodes    []ReviewCommentNode `json:"nodes"`
 	} `json:"comments"`
@@ -132,15 +270,37 @@ type ReviewCommentNode struct {
 	ReplyTo *struct {
 		ID string `json:"id"`
 	} `json:"replyTo"`
-	Author struct {
-		Login string `json:"login"`
-	} `json:"author"`
+	Author ActorNode `json:"author"`
 }
 
-func (n PullRequestNode) CommentsCount() int {
-	for _, review := range n.Reviews.Nodes {
-		return len(review.Comments.Nodes)
+// レビューやコメントをした人。GitHub Apps の場合 __typename が Bot になる
+type ActorNode struct {
+	Typename string `json:"__typename"`
+	Login    string `json:"login"`
+}
+
+func (n ActorNode) IsBot() bool {
+	return n.Typename == "Bot"
+}
+
+// マージコミットからマージの方法を推定する
+// 親が 1 つで PR 全体と同じ差分のコミットは squash、親が 1 つでそれ以外のコミットは rebase とみなす
+func (n PullRequestNode) MergeMethod() PullRequestMergeMethod {
+	switch {
+	case n.MergeCommit == nil:
+		return ""
+	case n.MergeCommit.Parents.TotalCount > 1:
+		return PullRequestMergeMethodMerge
+	case n.MergeCommit.Additions == n.Additions && n.MergeCommit.Deletions == n.Deletions:
+		return PullRequestMergeMethodSquash
+	default:
+		return PullRequestMergeMethodRebase
 	}
+}
 
-	return 0
+// すべてのレビューのコメント数の合計
+func (n PullRequestNode) CommentsCount() int {
+	return lo.SumBy(n.Reviews.Nodes, func(review ReviewNode) int {
+		return len(review.Comments.Nodes)
+	})
 }
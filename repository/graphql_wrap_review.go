@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+)
+
+const reviewRequestsLimit = 20
+
+const wrapReviewQuery = `
+query WrapReview($from: DateTime, $to: DateTime, $reviewAfterCursor: String, $reviewRequestsLimit: Int = 20) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      pullRequestReviewContributions(first: 100, after: $reviewAfterCursor) {
+        totalCount
+        pageInfo {
+          endCursor
+          hasNextPage
+        }
+        nodes {
+          pullRequestReview {
+            id
+            state
+            submittedAt
+            comments {
+              totalCount
+            }
+            pullRequest {
+              id
+              number
+              title
+              url
+              createdAt
+              author {
+                __typename
+                login
+              }
+              repository {
+                owner {
+                  login
+                }
+                name
+              }
+              timelineItems(first: $reviewRequestsLimit, itemTypes: [REVIEW_REQUESTED_EVENT]) {
+                nodes {
+                  ... on ReviewRequestedEvent {
+                    createdAt
+                    requestedReviewer {
+                      ... on User {
+                        login
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type WrapReviewsResponse struct {
+	Viewer struct {
+		ContributionsCollection struct {
+			PullRequestReviewContributions struct {
+				TotalCount int      `json:"totalCount"`
+				PageInfo   PageInfo `json:"pageInfo"`
+				Nodes      []struct {
+					PullRequestReview GivenReviewNode `json:"pullRequestReview"`
+				} `json:"nodes"`
+			} `json:"pullRequestReviewContributions"`
+		} `json:"contributionsCollection"`
+	} `json:"viewer"`
+}
+
+type GivenReviewNode struct {
+	ID          string    `json:"id"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submittedAt"`
+	Comments    struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	PullRequest struct {
+		ID         string    `json:"id"`
+		Number     int       `json:"number"`
+		Title      string    `json:"title"`
+		URL        string    `json:"url"`
+		CreatedAt  time.Time `json:"createdAt"`
+		Author     ActorNode `json:"author"`
+		Repository struct {
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+			Name string `json:"name"`
+		} `json:"repository"`
+		TimelineItems struct {
+			Nodes []ReviewRequestedEventNode `json:"nodes"`
+		} `json:"timelineItems"`
+	} `json:"pullRequest"`
+}
+
+type ReviewRequestedEventNode struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// チームへのリクエストの場合は login が空になる
+	RequestedReviewer struct {
+		Login string `json:"login"`
+	} `json:"requestedReviewer"`
+}
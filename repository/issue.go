@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// from ~ to の間にユーザーが作成した issue を返す
+func (r *GitHubClient) ListIssues(from, to time.Time) ([]*Issue, error) {
+	var nextCursor string
+	var issues []*Issue
+	for {
+		var response WrapIssuesResponse
+
+		variables := map[string]interface{}{
+			"from":        from,
+			"to":          to,
+			"labelsLimit": labelsLimit,
+		}
+
+		if nextCursor != "" {
+			variables["issueAfterCursor"] = nextCursor
+		}
+
+		slog.Debug(
+			"getting issues...",
+			"variables", variables,
+		)
+
+		if err := r.graphQLClient.Do(
+			wrapIssueQuery,
+			variables,
+			&response,
+		); err != nil {
+			return nil, err
+		}
+
+		contributions := response.Viewer.ContributionsCollection.IssueContributions
+		for _, node := range contributions.Nodes {
+			issues = append(issues, &Issue{
+				ID:              node.Issue.ID,
+				Number:          node.Issue.Number,
+				Title:           node.Issue.Title,
+				RepositoryOwner: node.Issue.Repository.Owner.Login,
+				RepositoryName:  node.Issue.Repository.Name,
+				State:           IssueState(node.Issue.State),
+				CreatedAt:       node.Issue.CreatedAt,
+				ClosedAt:        node.Issue.ClosedAt,
+				CommentsCount:   node.Issue.Comments.TotalCount,
+				Labels: lo.Map(node.Issue.Labels.Nodes, func(label LabelNode, _ int) string {
+					return label.Name
+				}),
+				URL: node.Issue.URL,
+			})
+		}
+
+		if !contributions.PageInfo.HasNextPage {
+			break
+		}
+
+		nextCursor = contributions.PageInfo.EndCursor
+
+		// レートリミット対策のために sleep
+		time.Sleep(1 * time.Second)
+	}
+
+	slog.Debug("got issues", "len", len(issues))
+
+	return issues, nil
+}
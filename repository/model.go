@@ -12,32 +12,79 @@ type PageInfo struct {
 }
 
 type PullRequest struct {
-	ID              string
-	Number          int
-	Title           string
-	RepositoryOwner string
-	RepositoryName  string
-	CreatedAt       time.Time
-	ClosedAt        null.Time
-	MergedAt        null.Time
-	State           PullRequestState
-	CommitsCount    int
-	CommentsCount   int
-	Reviews         []PullRequestReview
-	URL             string
+	ID               string
+	Number           int
+	Title            string
+	RepositoryOwner  string
+	RepositoryName   string
+	RepositoryIsFork bool
+	IsDraft          bool
+	Labels           []string
+	CreatedAt        time.Time
+	// 最後に更新された日時。コメントやコミットの追加でも更新される
+	UpdatedAt time.Time
+	ClosedAt  null.Time
+	MergedAt  null.Time
+	// マージしたユーザー。マージされていない場合と削除されたユーザーの場合は空
+	MergedBy string
+	// マージコミットから推定したマージの方法。マージされていない場合は空
+	MergeMethod   PullRequestMergeMethod
+	State         PullRequestState
+	CommitsCount  int
+	CommentsCount int
+	Additions     int
+	Deletions     int
+	ChangedFiles  int
+	Reviews       []PullRequestReview
+	// 作成日時の昇順
+	TimelineEvents []PullRequestTimelineEvent
+	URL            string
+}
+
+// owner/repo 形式のリポジトリ名を返す
+func (pr *PullRequest) RepositoryFullName() string {
+	return pr.RepositoryOwner + "/" + pr.RepositoryName
+}
+
+// 追加・削除した行数の合計
+func (pr *PullRequest) ChangedLines() int {
+	return pr.Additions + pr.Deletions
 }
 
 type PullRequestReview struct {
-	ID       string
-	Author   string
-	State    string
-	Comments []PullRequestComment
+	ID     string
+	Author string
+	// GitHub Apps によるレビュー
+	AuthorIsBot bool
+	State       string
+	// PENDING のレビューは空
+	SubmittedAt null.Time
+	Comments    []PullRequestComment
+}
+
+type PullRequestTimelineEvent struct {
+	// ReadyForReviewEvent などの GraphQL の型名
+	Type      string
+	CreatedAt time.Time
+	// イベントを起こしたユーザー。ClosedEvent 以外と削除されたユーザーの場合は空
+	Actor string
+	// クローズした理由 (NOT_PLANNED など)。ClosedEvent 以外と理由がない場合は空
+	StateReason string
 }
 
+const (
+	TimelineEventReadyForReview  = "ReadyForReviewEvent"
+	TimelineEventConvertToDraft  = "ConvertToDraftEvent"
+	TimelineEventReviewRequested = "ReviewRequestedEvent"
+	TimelineEventClosed          = "ClosedEvent"
+)
+
 type PullRequestComment struct {
-	ID      string
-	Author  string
-	ReplyTo string
+	ID     string
+	Author string
+	// GitHub Apps によるコメント
+	AuthorIsBot bool
+	ReplyTo     string
 }
 
 type PullRequestState string
@@ -48,6 +95,15 @@ const (
 	PullRequestStateMerged PullRequestState = "MERGED"
 )
 
+// GraphQL の PullRequestMergeMethod と同じ値
+type PullRequestMergeMethod string
+
+const (
+	PullRequestMergeMethodMerge  PullRequestMergeMethod = "MERGE"
+	PullRequestMergeMethodSquash PullRequestMergeMethod = "SQUASH"
+	PullRequestMergeMethodRebase PullRequestMergeMethod = "REBASE"
+)
+
 func (s PullRequestState) String() string {
 	return string(s)
 }
@@ -64,3 +120,93 @@ func FromString(s string) PullRequestState {
 		panic("invalid PullRequestState")
 	}
 }
+
+// ユーザーが他の PR につけたレビュー
+type GivenReview struct {
+	ID            string
+	State         string
+	SubmittedAt   time.Time
+	CommentsCount int
+	PullRequest   ReviewedPullRequest
+}
+
+// レビューした PR
+type ReviewedPullRequest struct {
+	ID     string
+	Number int
+	Title  string
+	Author string
+	// dependabot などの GitHub Apps が作成した PR
+	AuthorIsBot     bool
+	RepositoryOwner string
+	RepositoryName  string
+	CreatedAt       time.Time
+	ReviewRequests  []ReviewRequest
+	URL             string
+}
+
+func (pr *ReviewedPullRequest) RepositoryFullName() string {
+	return pr.RepositoryOwner + "/" + pr.RepositoryName
+}
+
+type ReviewRequest struct {
+	// チームへのリクエストの場合は空
+	Reviewer    string
+	RequestedAt time.Time
+}
+
+type Issue struct {
+	ID              string
+	Number          int
+	Title           string
+	RepositoryOwner string
+	RepositoryName  string
+	State           IssueState
+	CreatedAt       time.Time
+	ClosedAt        null.Time
+	CommentsCount   int
+	Labels          []string
+	URL             string
+}
+
+func (i *Issue) RepositoryFullName() string {
+	return i.RepositoryOwner + "/" + i.RepositoryName
+}
+
+type IssueState string
+
+const (
+	IssueStateOpen   IssueState = "OPEN"
+	IssueStateClosed IssueState = "CLOSED"
+)
+
+type CommitContributions struct {
+	// totalCommitContributions。Contributions に含まれないプライベートリポジトリへのコミットも数える
+	TotalCount    int
+	Contributions []CommitContribution
+}
+
+// リポジトリ・日ごとのコミット数
+type CommitContribution struct {
+	RepositoryOwner string
+	RepositoryName  string
+	OccurredAt      time.Time
+	CommitCount     int
+}
+
+func (c *CommitContribution) RepositoryFullName() string {
+	return c.RepositoryOwner + "/" + c.RepositoryName
+}
+
+type ContributionCalendar struct {
+	TotalContributions int
+	// 日付の昇順
+	Days []ContributionDay
+}
+
+type ContributionDay struct {
+	// UTC の 0 時
+	Date    time.Time
+	Weekday time.Weekday
+	Count   int
+}
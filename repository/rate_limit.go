@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// https://docs.github.com/ja/rest/rate-limit/rate-limit?apiVersion=2022-11-28#get-rate-limit-status-for-the-authenticated-user
+func (r *GitHubClient) GetRateLimit() (*RateLimit, error) {
+	var response struct {
+		Resources struct {
+			GraphQL struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"graphql"`
+		} `json:"resources"`
+	}
+	if err := r.restClient.Get("rate_limit", &response); err != nil {
+		return nil, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+
+	return &RateLimit{
+		Limit:     response.Resources.GraphQL.Limit,
+		Remaining: response.Resources.GraphQL.Remaining,
+		ResetAt:   time.Unix(response.Resources.GraphQL.Reset, 0),
+	}, nil
+}
+
+func (r *GitHubClient) Host() string {
+	return r.host
+}
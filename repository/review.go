@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// from ~ to の間にユーザーがつけたレビューを返す
+func (r *GitHubClient) ListGivenReviews(from, to time.Time) ([]*GivenReview, error) {
+	var nextCursor string
+	var reviews []*GivenReview
+	for {
+		var response WrapReviewsResponse
+
+		variables := map[string]interface{}{
+			"from":                from,
+			"to":                  to,
+			"reviewRequestsLimit": reviewRequestsLimit,
+		}
+
+		if nextCursor != "" {
+			variables["reviewAfterCursor"] = nextCursor
+		}
+
+		slog.Debug(
+			"getting reviews...",
+			"variables", variables,
+		)
+
+		if err := r.graphQLClient.Do(
+			wrapReviewQuery,
+			variables,
+			&response,
+		); err != nil {
+			return nil, err
+		}
+
+		contributions := response.Viewer.ContributionsCollection.PullRequestReviewContributions
+		for _, node := range contributions.Nodes {
+			review := node.PullRequestReview
+
+			reviews = append(reviews, &GivenReview{
+				ID:            review.ID,
+				State:         review.State,
+				SubmittedAt:   review.SubmittedAt,
+				CommentsCount: review.Comments.TotalCount,
+				PullRequest: ReviewedPullRequest{
+					ID:              review.PullRequest.ID,
+					Number:          review.PullRequest.Number,
+					Title:           review.PullRequest.Title,
+					Author:          review.PullRequest.Author.Login,
+					AuthorIsBot:     review.PullRequest.Author.IsBot(),
+					RepositoryOwner: review.PullRequest.Repository.Owner.Login,
+					RepositoryName:  review.PullRequest.Repository.Name,
+					CreatedAt:       review.PullRequest.CreatedAt,
+					ReviewRequests: lo.Map(review.PullRequest.TimelineItems.Nodes, func(event ReviewRequestedEventNode, _ int) ReviewRequest {
+						return ReviewRequest{
+							Reviewer:    event.RequestedReviewer.Login,
+							RequestedAt: event.CreatedAt,
+						}
+					}),
+					URL: review.PullRequest.URL,
+				},
+			})
+		}
+
+		if !contributions.PageInfo.HasNextPage {
+			break
+		}
+
+		nextCursor = contributions.PageInfo.EndCursor
+
+		// レートリミット対策のために sleep
+		time.Sleep(1 * time.Second)
+	}
+
+	slog.Debug("got reviews", "len", len(reviews))
+
+	return reviews, nil
+}
@@ -31,6 +31,7 @@ func (r *GitHubClient) GetMe() (*PublicUser, error) {
 	if err := r.restClient.Get("user", &user); err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	r.authenticatedUser = &user
 
 	return &user, nil
 }
@@ -0,0 +1,337 @@
+package wrapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/samber/lo"
+)
+
+// 集計結果から判定した実績
+type WrappedResultAchievements struct {
+	// 獲得した実績 (組み込みの実績、設定ファイルの実績の順)
+	Earned []Achievement
+	// 獲得しなかった実績の名前
+	Locked []string
+}
+
+type Achievement struct {
+	Name        string
+	Description string
+	// 獲得した理由
+	Reason string
+}
+
+type achievementRule struct {
+	name        string
+	description string
+	// 獲得した場合は理由を返す。判定に必要なセクションが集計されていない場合は獲得しない
+	evaluate func(result *WrappedResult) (string, bool, error)
+}
+
+// 組み込みの実績
+var builtinAchievementRules = []achievementRule{
+	{
+		name:        "Speed Demon",
+		description: "Got a pull request merged in under 10 minutes",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.PullRequests == nil || result.PullRequests.ShortLivePullRequests == nil || len(result.PullRequests.ShortLivePullRequests.Items) == 0 {
+				return "", false, nil
+			}
+			item := result.PullRequests.ShortLivePullRequests.Items[0]
+			return pullRequestDurationReason("was merged in", item), item.Duration < 10*time.Minute, nil
+		},
+	},
+	{
+		name:        "Marathoner",
+		description: "Kept a pull request alive for 100 days before merging",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.PullRequests == nil || result.PullRequests.LongLiveRequests == nil || len(result.PullRequests.LongLiveRequests.Items) == 0 {
+				return "", false, nil
+			}
+			item := result.PullRequests.LongLiveRequests.Items[0]
+			return pullRequestDurationReason("took", item), item.Duration >= 100*24*time.Hour, nil
+		},
+	},
+	{
+		name:        "Centurion",
+		description: "Merged 100 pull requests",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.PullRequests == nil {
+				return "", false, nil
+			}
+			return fmt.Sprintf("merged %d pull requests", result.PullRequests.MergedCount), result.PullRequests.MergedCount >= 100, nil
+		},
+	},
+	{
+		name:        "Polyglot",
+		description: "Opened pull requests in 10 repositories",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.PullRequests == nil {
+				return "", false, nil
+			}
+			return fmt.Sprintf("opened pull requests in %d repositories", result.PullRequests.RepositoryCount), result.PullRequests.RepositoryCount >= 10, nil
+		},
+	},
+	{
+		name:        "Gatekeeper",
+		description: "Gave 100 reviews",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.Reviews == nil {
+				return "", false, nil
+			}
+			return fmt.Sprintf("gave %d reviews", result.Reviews.TotalCount), result.Reviews.TotalCount >= 100, nil
+		},
+	},
+	{
+		name:        "Clean Sheet",
+		description: "Got 90% of 10 or more reviewed pull requests approved without requested changes",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.ReviewRounds == nil {
+				return "", false, nil
+			}
+			rounds := result.ReviewRounds
+			return fmt.Sprintf("%d of %d reviewed pull requests were approved on the first pass", rounds.FirstPassApprovedCount, rounds.ReviewedMergedCount),
+				rounds.ReviewedMergedCount >= 10 && rounds.FirstPassApprovalRate >= 0.9, nil
+		},
+	},
+	{
+		name:        "Unstoppable",
+		description: "Contributed 30 days in a row",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.Calendar == nil {
+				return "", false, nil
+			}
+			streak := result.Calendar.LongestStreak
+			return fmt.Sprintf("contributed %d days in a row from %s to %s", streak.Days, streak.From, streak.To), streak.Days >= 30, nil
+		},
+	},
+	{
+		name:        "Night Owl",
+		description: "Was most active late at night",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.Rhythm == nil {
+				return "", false, nil
+			}
+			return chronotypeReason(result.Rhythm.AllActivities, nightOwlFromHour, nightOwlToHour), result.Rhythm.Chronotype == ChronotypeNightOwl, nil
+		},
+	},
+	{
+		name:        "Early Bird",
+		description: "Was most active early in the morning",
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			if result.Rhythm == nil {
+				return "", false, nil
+			}
+			return chronotypeReason(result.Rhythm.AllActivities, earlyBirdFromHour, earlyBirdToHour), result.Rhythm.Chronotype == ChronotypeEarlyBird, nil
+		},
+	},
+}
+
+func pullRequestDurationReason(verb string, item PullRequestDurationItem) string {
+	return fmt.Sprintf(
+		"%s/%s#%d %s %s",
+		item.PullRequest.Owner,
+		item.PullRequest.Repo,
+		item.PullRequest.Number,
+		verb,
+		item.Duration.Round(time.Second),
+	)
+}
+
+func chronotypeReason(histogram ActivityHistogram, from, to int) string {
+	total := lo.Sum(lo.Map(lo.Range(7), func(weekday int, _ int) int {
+		return histogram.weekdayTotal(time.Weekday(weekday))
+	}))
+	if total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"%.0f%% of the activities were between %02d:00 and %02d:00",
+		float64(histogram.hoursTotal(from, to))/float64(total)*100,
+		from,
+		to,
+	)
+}
+
+// 組み込みの実績と設定ファイルの実績の判定を組み立てる
+func newAchievementRules(cfg *config.Config) ([]achievementRule, error) {
+	rules := append([]achievementRule{}, builtinAchievementRules...)
+	for i, rule := range cfg.Achievements {
+		configRule, err := newConfigAchievementRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid achievement %d (%s): %w", i, rule.Name, err)
+		}
+		rules = append(rules, configRule)
+	}
+
+	return rules, nil
+}
+
+func evaluateAchievements(result *WrappedResult, rules []achievementRule) (*WrappedResultAchievements, error) {
+	var achievements WrappedResultAchievements
+	for _, rule := range rules {
+		reason, earned, err := rule.evaluate(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate achievement %s: %w", rule.name, err)
+		}
+		if !earned {
+			achievements.Locked = append(achievements.Locked, rule.name)
+			continue
+		}
+
+		achievements.Earned = append(achievements.Earned, Achievement{
+			Name:        rule.name,
+			Description: rule.description,
+			Reason:      reason,
+		})
+	}
+
+	return &achievements, nil
+}
+
+var wrappedResultType = reflect.TypeOf(WrappedResult{})
+
+// 設定ファイルの実績を組み込みの実績の名前と WrappedResult のフィールドで検証する。config.Flags.Load に渡す
+func AchievementValidator() config.AchievementValidator {
+	return config.AchievementValidator{
+		ReservedNames: lo.Map(builtinAchievementRules, func(rule achievementRule, _ int) string {
+			return rule.name
+		}),
+		ValidateMetric: func(metric string) error {
+			_, err := achievementMetricPath(metric)
+			return err
+		},
+	}
+}
+
+// metric のパスが WrappedResult の数値のフィールドを指しているか確かめ、フィールド名に分けて返す
+func achievementMetricPath(metric string) ([]string, error) {
+	path := strings.Split(metric, ".")
+	t := wrappedResultType
+	for _, name := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unknown metric %q", metric)
+		}
+
+		field, ok := t.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return nil, fmt.Errorf("unknown metric %q", metric)
+		}
+		t = field.Type
+	}
+	if t != durationType && t.Kind() != reflect.Bool && !isNumberKind(t.Kind()) {
+		return nil, fmt.Errorf("metric %q is not a number", metric)
+	}
+
+	return path, nil
+}
+
+// 設定ファイルの実績の判定を組み立てる
+func newConfigAchievementRule(rule config.AchievementRule) (achievementRule, error) {
+	path, err := achievementMetricPath(rule.Metric)
+	if err != nil {
+		return achievementRule{}, err
+	}
+
+	operator := rule.Operator
+	if operator == "" {
+		operator = ">="
+	}
+
+	return achievementRule{
+		name:        rule.Name,
+		description: rule.Description,
+		evaluate: func(result *WrappedResult) (string, bool, error) {
+			value, ok := metricValue(reflect.ValueOf(result), path)
+			if !ok {
+				return "", false, nil
+			}
+
+			earned, err := compareAchievementValue(value, operator, rule.Threshold)
+			if err != nil {
+				return "", false, err
+			}
+
+			reason := fmt.Sprintf(
+				"%s is %s (%s %s)",
+				rule.Metric,
+				strconv.FormatFloat(value, 'f', -1, 64),
+				operator,
+				strconv.FormatFloat(rule.Threshold, 'f', -1, 64),
+			)
+			return reason, earned, nil
+		},
+	}, nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// path をたどった先の値を float64 にする。duration は時間単位、bool は 1 / 0。途中に nil がある場合は false
+func metricValue(v reflect.Value, path []string) (float64, bool) {
+	for _, name := range path {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return 0, false
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByName(name)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).Hours(), true
+	case v.Kind() == reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	default:
+		return v.Float(), true
+	}
+}
+
+func compareAchievementValue(value float64, operator string, threshold float64) (bool, error) {
+	switch operator {
+	case ">=":
+		return value >= threshold, nil
+	case ">":
+		return value > threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case "<":
+		return value < threshold, nil
+	case "==":
+		return value == threshold, nil
+	default:
+		return false, fmt.Errorf("unknown operator %q", operator)
+	}
+}
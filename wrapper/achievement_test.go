@@ -0,0 +1,38 @@
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAchievementMetricPath(t *testing.T) {
+	tests := []struct {
+		metric  string
+		want    []string
+		wantErr bool
+	}{
+		{metric: "PullRequests.MergedCount", want: []string{"PullRequests", "MergedCount"}},
+		{metric: "Calendar.LongestStreak.Days", want: []string{"Calendar", "LongestStreak", "Days"}},
+		{metric: "PullRequests.MergedCont", wantErr: true},
+		{metric: "PullRequests", wantErr: true},
+		{metric: "Login", wantErr: true},
+		{metric: "PullRequests.MergedCount.Value", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			got, err := achievementMetricPath(tt.metric)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("achievementMetricPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("achievementMetricPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareAchievementValue_unknownOperator(t *testing.T) {
+	if _, err := compareAchievementValue(1, "=>", 1); err == nil {
+		t.Error("compareAchievementValue() error = nil")
+	}
+}
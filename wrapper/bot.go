@@ -0,0 +1,154 @@
+package wrapper
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 設定ファイルの bots に加えて、常に bot とみなすユーザーの glob パターン
+// GitHub Apps は __typename で判定できるが、Apps 以外の bot アカウントのためにパターンでも判定する
+// path.Match では [] が文字クラスになるので、[bot] で終わるログインはエスケープして書く
+var defaultBotPatterns = []string{`*\[bot\]`, "dependabot*", "renovate*", "github-actions*", "copilot-*"}
+
+// 自分の PR での bot の活動
+type WrappedResultBots struct {
+	Login string
+	// bot の活動を PR やレビューの集計から除外したか (--include-bots を指定しなかった場合 true)
+	Excluded bool
+	// 自分の PR に bot がつけたレビューの数
+	ReviewCount int
+	// 自分の PR に bot が書いたレビューコメントの数
+	CommentCount int
+	// 当年にマージされた PR の数
+	MergedCount int
+	// マージ前に作成者以外の人間からレビューされた PR の数
+	HumanReviewedCount int
+	// MergedCount のうち HumanReviewedCount の割合 (0 ~ 1)
+	HumanReviewedRate float64
+	// マージ前に bot だけからレビューされた PR の数
+	BotOnlyReviewedCount int
+	// bot が作成した PR に自分がつけたレビューの数
+	GivenReviewCount int
+	// レビュー・コメントの数が多かった bot
+	TopBots Ranking[CountRankingItem]
+}
+
+// ユーザーが bot かどうかを判定する
+type botMatcher struct {
+	patterns []string
+}
+
+func newBotMatcher(cfg *config.Config) botMatcher {
+	return botMatcher{patterns: lowerAll(append(append([]string{}, defaultBotPatterns...), cfg.Bots...))}
+}
+
+// isBotType は GraphQL の __typename が Bot だったかどうか
+func (m botMatcher) isBot(login string, isBotType bool) bool {
+	if isBotType {
+		return true
+	}
+
+	return lo.ContainsBy(m.patterns, func(pattern string) bool {
+		// 設定ファイルで検証済み
+		matched, _ := path.Match(pattern, strings.ToLower(login))
+		return matched
+	})
+}
+
+func WrapBots(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultBots, error) {
+	source, err := fetchPullRequestSource(repo, cfg, repository.PullRequestFields{Reviews: true, ReviewComments: true})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return wrapBots(source.login, source.pullRequestsWithBots, reviews, cfg), nil
+}
+
+func wrapBots(login string, pullRequests []*repository.PullRequest, reviews []*repository.GivenReview, cfg *config.Config) *WrappedResultBots {
+	bots := newBotMatcher(cfg)
+	isBotReview := func(review repository.PullRequestReview) bool {
+		return bots.isBot(review.Author, review.AuthorIsBot)
+	}
+
+	activityByBot := map[string]int{}
+	result := WrappedResultBots{
+		Login:    login,
+		Excluded: !cfg.IncludeBots,
+		GivenReviewCount: lo.CountBy(reviews, func(review *repository.GivenReview) bool {
+			return bots.isBot(review.PullRequest.Author, review.PullRequest.AuthorIsBot)
+		}),
+	}
+	for _, pr := range pullRequests {
+		for _, review := range pr.Reviews {
+			if review.SubmittedAt.Valid && isBotReview(review) {
+				result.ReviewCount++
+				activityByBot[review.Author]++
+			}
+			for _, comment := range review.Comments {
+				if bots.isBot(comment.Author, comment.AuthorIsBot) {
+					result.CommentCount++
+					activityByBot[comment.Author]++
+				}
+			}
+		}
+
+		if pr.State != repository.PullRequestStateMerged || !pr.MergedAt.Valid {
+			continue
+		}
+
+		result.MergedCount++
+		reviewed := reviewsBeforeMerge(login, pr)
+		reviewsByBot := lo.CountBy(reviewed, isBotReview)
+		switch {
+		case reviewsByBot < len(reviewed):
+			result.HumanReviewedCount++
+		case reviewsByBot > 0:
+			result.BotOnlyReviewedCount++
+		}
+	}
+	if result.MergedCount > 0 {
+		result.HumanReviewedRate = float64(result.HumanReviewedCount) / float64(result.MergedCount)
+	}
+	result.TopBots = pickTopNCountRankingItemDesc(
+		// 削除されたユーザー (ghost) は数えない
+		lo.OmitByKeys(activityByBot, []string{""}),
+		cfg.TopN(config.RankingTopBots),
+	)
+
+	return &result
+}
+
+// bot のレビュー・レビューコメントを取り除いた PR を返す。元の PR は変更しない
+func withoutBotActivity(pullRequests []*repository.PullRequest, bots botMatcher) []*repository.PullRequest {
+	return lo.Map(pullRequests, func(pr *repository.PullRequest, _ int) *repository.PullRequest {
+		copied := *pr
+		copied.Reviews = lo.FilterMap(pr.Reviews, func(review repository.PullRequestReview, _ int) (repository.PullRequestReview, bool) {
+			review.Comments = lo.Filter(review.Comments, func(comment repository.PullRequestComment, _ int) bool {
+				return !bots.isBot(comment.Author, comment.AuthorIsBot)
+			})
+
+			return review, !bots.isBot(review.Author, review.AuthorIsBot)
+		})
+		copied.CommentsCount = lo.SumBy(copied.Reviews, func(review repository.PullRequestReview) int {
+			return len(review.Comments)
+		})
+
+		return &copied
+	})
+}
+
+func lowerAll(values []string) []string {
+	return lo.Map(values, func(value string, _ int) string {
+		return strings.ToLower(value)
+	})
+}
@@ -0,0 +1,36 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+)
+
+func TestBotMatcher_isBot(t *testing.T) {
+	bots := newBotMatcher(&config.Config{Bots: []string{"ci-*"}})
+
+	tests := []struct {
+		login     string
+		isBotType bool
+		want      bool
+	}{
+		{login: "robert", want: false},
+		{login: "hugo", want: false},
+		{login: "jacob", want: false},
+		{login: "bot", want: false},
+		{login: "dependabot[bot]", want: true},
+		{login: "github-actions[bot]", want: true},
+		{login: "my-app[bot]", want: true},
+		{login: "My-App[Bot]", want: true},
+		{login: "renovate", want: true},
+		{login: "ci-runner", want: true},
+		{login: "alice", isBotType: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			if got := bots.isBot(tt.login, tt.isBotType); got != tt.want {
+				t.Errorf("isBot(%q, %v) = %v, want %v", tt.login, tt.isBotType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package wrapper
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/montanaflynn/stats"
+	"github.com/samber/lo"
+)
+
+type WrappedResultCalendar struct {
+	Login string
+	// 当年のコントリビューションの数
+	TotalContributions int
+	// 連続してコントリビューションした最長の期間
+	LongestStreak Streak
+	// 集計期間の最終日 (今年の場合は今日) まで続いている連続コントリビューション
+	CurrentStreak Streak
+	// 最もコントリビューションが多かった日
+	MostActiveDay DayCount
+	// 最もコントリビューションが多かった週 (日曜始まり)
+	MostActiveWeek DayCount
+	// コントリビューションがなかった日の数
+	ZeroContributionDays int
+	// GitHub のプロフィールと同じ形式のヒートマップ
+	Heatmap ContributionHeatmap
+}
+
+type DayCount struct {
+	// 2006-01-02 形式
+	Date  string
+	Count int
+}
+
+type Streak struct {
+	Days int
+	// 2006-01-02 形式。Days が 0 の場合は空
+	From string
+	To   string
+}
+
+type ContributionHeatmap struct {
+	// 日曜始まりの週ごとのマス。最初と最後の週は 7 日に満たないことがある
+	Weeks []ContributionHeatmapWeek
+}
+
+type ContributionHeatmapWeek struct {
+	Days []ContributionHeatmapDay
+}
+
+type ContributionHeatmapDay struct {
+	// 2006-01-02 形式
+	Date    string
+	Weekday time.Weekday
+	Count   int
+	// 0 ~ 4 の色の濃さ。0 はコントリビューションなし
+	Level int
+}
+
+func WrapCalendar(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultCalendar, error) {
+	user, err := repo.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	calendar, err := repo.GetContributionCalendar(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contribution calendar: %w", err)
+	}
+
+	// 今日がいつかは年の境界と同じタイムゾーンで決める
+	return wrapCalendar(user.Login, calendar, time.Now().In(cfg.Location())), nil
+}
+
+func wrapCalendar(login string, calendar *repository.ContributionCalendar, now time.Time) *WrappedResultCalendar {
+	days := make([]repository.ContributionDay, len(calendar.Days))
+	copy(days, calendar.Days)
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+
+	// 未来の日はまだコントリビューションできないので、ストリークの計算からは除く
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	pastDays := lo.Filter(days, func(day repository.ContributionDay, _ int) bool {
+		return !day.Date.After(today)
+	})
+
+	return &WrappedResultCalendar{
+		Login:              login,
+		TotalContributions: calendar.TotalContributions,
+		LongestStreak:      longestStreak(pastDays),
+		CurrentStreak:      currentStreak(pastDays, today),
+		MostActiveDay: func() DayCount {
+			if len(days) == 0 {
+				return DayCount{}
+			}
+
+			// 同じ数の日が複数ある場合は早い日にする
+			day := lo.MaxBy(days, func(a, b repository.ContributionDay) bool {
+				return a.Count > b.Count
+			})
+			return DayCount{Date: formatDate(day.Date), Count: day.Count}
+		}(),
+		MostActiveWeek: mostActiveWeek(days),
+		ZeroContributionDays: lo.CountBy(pastDays, func(day repository.ContributionDay) bool {
+			return day.Count == 0
+		}),
+		Heatmap: newContributionHeatmap(days),
+	}
+}
+
+func longestStreak(days []repository.ContributionDay) Streak {
+	var longest, current Streak
+	for i, day := range days {
+		if day.Count == 0 {
+			current = Streak{}
+			continue
+		}
+
+		if current.Days == 0 || !days[i-1].Date.AddDate(0, 0, 1).Equal(day.Date) {
+			current = Streak{From: formatDate(day.Date)}
+		}
+		current.Days++
+		current.To = formatDate(day.Date)
+
+		if current.Days > longest.Days {
+			longest = current
+		}
+	}
+
+	return longest
+}
+
+// 最終日から遡って連続している日数を返す
+// 今日の分はまだコントリビューションしていないだけかもしれないので、今日が 0 の場合は昨日から数える
+func currentStreak(days []repository.ContributionDay, today time.Time) Streak {
+	if len(days) > 0 && days[len(days)-1].Count == 0 && days[len(days)-1].Date.Equal(today) {
+		days = days[:len(days)-1]
+	}
+
+	var streak Streak
+	for i := len(days) - 1; i >= 0; i-- {
+		if days[i].Count == 0 {
+			break
+		}
+		if i < len(days)-1 && !days[i].Date.AddDate(0, 0, 1).Equal(days[i+1].Date) {
+			break
+		}
+
+		streak.Days++
+		streak.From = formatDate(days[i].Date)
+		if streak.To == "" {
+			streak.To = formatDate(days[i].Date)
+		}
+	}
+
+	return streak
+}
+
+func mostActiveWeek(days []repository.ContributionDay) DayCount {
+	countByWeek := map[string]int{}
+	for _, day := range days {
+		weekStart := day.Date.AddDate(0, 0, -int(day.Date.Weekday()))
+		countByWeek[formatDate(weekStart)] += day.Count
+	}
+
+	var week DayCount
+	for date, count := range countByWeek {
+		if count > week.Count || (count == week.Count && count > 0 && date < week.Date) {
+			week = DayCount{Date: date, Count: count}
+		}
+	}
+
+	return week
+}
+
+func newContributionHeatmap(days []repository.ContributionDay) ContributionHeatmap {
+	level := newQuartileLevel(lo.Map(days, func(day repository.ContributionDay, _ int) int {
+		return day.Count
+	}))
+
+	var heatmap ContributionHeatmap
+	for _, day := range days {
+		if len(heatmap.Weeks) == 0 || day.Date.Weekday() == time.Sunday {
+			heatmap.Weeks = append(heatmap.Weeks, ContributionHeatmapWeek{})
+		}
+
+		week := &heatmap.Weeks[len(heatmap.Weeks)-1]
+		week.Days = append(week.Days, ContributionHeatmapDay{
+			Date:    formatDate(day.Date),
+			Weekday: day.Date.Weekday(),
+			Count:   day.Count,
+			Level:   level(day.Count),
+		})
+	}
+
+	return heatmap
+}
+
+// GitHub と同じく、0 でない件数の四分位数で 0 ~ 4 の色の濃さを決める関数を返す
+func newQuartileLevel(counts []int) func(count int) int {
+	nonZeroCounts := lo.FilterMap(counts, func(count int, _ int) (float64, bool) {
+		return float64(count), count > 0
+	})
+	var quartiles stats.Quartiles
+	if len(nonZeroCounts) > 0 {
+		quartiles = lo.Must(stats.Quartile(nonZeroCounts))
+	}
+
+	return func(count int) int {
+		switch c := float64(count); {
+		case count == 0:
+			return 0
+		case c <= quartiles.Q1:
+			return 1
+		case c <= quartiles.Q2:
+			return 2
+		case c <= quartiles.Q3:
+			return 3
+		default:
+			return 4
+		}
+	}
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,164 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+// from から 1 日ずつ counts のコントリビューションがあった日を返す
+func contributionDays(from time.Time, counts ...int) []repository.ContributionDay {
+	days := make([]repository.ContributionDay, len(counts))
+	for i, count := range counts {
+		date := from.AddDate(0, 0, i)
+		days[i] = repository.ContributionDay{Date: date, Weekday: date.Weekday(), Count: count}
+	}
+
+	return days
+}
+
+func TestLongestStreak(t *testing.T) {
+	// 月曜日
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		days []repository.ContributionDay
+		want Streak
+	}{
+		{
+			name: "no days",
+			want: Streak{},
+		},
+		{
+			name: "no contributions",
+			days: contributionDays(from, 0, 0, 0),
+			want: Streak{},
+		},
+		{
+			name: "zero-count day breaks the streak",
+			days: contributionDays(from, 1, 2, 0, 1, 1, 1, 0),
+			want: Streak{Days: 3, From: "2024-01-04", To: "2024-01-06"},
+		},
+		{
+			name: "missing day breaks the streak",
+			days: append(contributionDays(from, 1, 1), contributionDays(from.AddDate(0, 0, 3), 1, 1, 1)...),
+			want: Streak{Days: 3, From: "2024-01-04", To: "2024-01-06"},
+		},
+		{
+			name: "earlier streak wins a tie",
+			days: contributionDays(from, 1, 1, 0, 1, 1),
+			want: Streak{Days: 2, From: "2024-01-01", To: "2024-01-02"},
+		},
+		{
+			name: "streak across the end of a month",
+			days: contributionDays(time.Date(2024, time.January, 30, 0, 0, 0, 0, time.UTC), 1, 1, 1, 1),
+			want: Streak{Days: 4, From: "2024-01-30", To: "2024-02-02"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := longestStreak(tt.days); got != tt.want {
+				t.Errorf("longestStreak() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentStreak(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	today := from.AddDate(0, 0, 4)
+
+	tests := []struct {
+		name string
+		days []repository.ContributionDay
+		want Streak
+	}{
+		{
+			name: "no days",
+			want: Streak{},
+		},
+		{
+			name: "contributed today",
+			days: contributionDays(from, 1, 0, 1, 1, 1),
+			want: Streak{Days: 3, From: "2024-03-03", To: "2024-03-05"},
+		},
+		{
+			name: "zero-count today does not break the streak",
+			days: contributionDays(from, 0, 1, 1, 1, 0),
+			want: Streak{Days: 3, From: "2024-03-02", To: "2024-03-04"},
+		},
+		{
+			name: "zero-count yesterday breaks the streak",
+			days: contributionDays(from, 1, 1, 1, 0, 0),
+			want: Streak{},
+		},
+		{
+			name: "missing day breaks the streak",
+			days: append(contributionDays(from, 1, 1), contributionDays(from.AddDate(0, 0, 3), 1, 1)...),
+			want: Streak{Days: 2, From: "2024-03-04", To: "2024-03-05"},
+		},
+		{
+			// 過去の年は today が集計期間より後になるので、最終日の 0 はそのまま途切れたものとする
+			name: "zero-count last day of a past period",
+			days: contributionDays(from.AddDate(0, 0, -5), 1, 1, 1, 1, 0),
+			want: Streak{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := currentStreak(tt.days, today); got != tt.want {
+				t.Errorf("currentStreak() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostActiveWeek(t *testing.T) {
+	tests := []struct {
+		name string
+		days []repository.ContributionDay
+		want DayCount
+	}{
+		{
+			name: "no days",
+			want: DayCount{},
+		},
+		{
+			name: "no contributions",
+			days: contributionDays(time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC), 0, 0, 0),
+			want: DayCount{},
+		},
+		{
+			name: "week starts on Sunday",
+			// 2024-03-09 (土) までと 2024-03-10 (日) からで別の週になる
+			days: contributionDays(time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC), 2, 2, 3),
+			want: DayCount{Date: "2024-03-03", Count: 4},
+		},
+		{
+			// 2024-01-01 は月曜日なので、最初の週は前の年の日曜日から始まる
+			name: "partial week at the start of the year",
+			days: contributionDays(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), 1, 1, 1, 1, 1, 1, 1),
+			want: DayCount{Date: "2023-12-31", Count: 6},
+		},
+		{
+			// 2024-12-31 は火曜日なので、最後の週は 3 日だけ
+			name: "partial week at the end of the year",
+			days: contributionDays(time.Date(2024, time.December, 22, 0, 0, 0, 0, time.UTC), 1, 1, 1, 1, 1, 1, 0, 3, 3, 1),
+			want: DayCount{Date: "2024-12-29", Count: 7},
+		},
+		{
+			name: "earlier week wins a tie",
+			days: contributionDays(time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC), 1, 0, 0, 0, 0, 0, 1, 2, 0, 0, 0, 0, 0, 0),
+			want: DayCount{Date: "2024-03-03", Count: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mostActiveWeek(tt.days); got != tt.want {
+				t.Errorf("mostActiveWeek() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package wrapper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// レビューやスレッドでのやりとりから見た、一緒に仕事をした人
+type WrappedResultCollaboration struct {
+	Login string
+	// 自分の PR をレビューした回数が多かった人
+	TopReviewers Ranking[CountRankingItem]
+	// 自分の PR のスレッドで返信しあった回数が多かった人
+	TopDiscussionPartners Ranking[CountRankingItem]
+	// レビュー・返信を合わせて、やりとりが多かった人
+	TopCollaborators Ranking[CountRankingItem]
+	// 当年にやりとりがあり、アカウントを作成してから前年までにやりとりがなかった人 (名前順)
+	// --skip-new-collaborators を指定した場合は nil
+	NewCollaborators []string
+	// やりとりの重み付き有向グラフ
+	Graph CollaborationGraph
+}
+
+type CollaborationGraph struct {
+	// ユーザー名 (名前順)
+	Nodes []string
+	// From, To, Kind の順に並べたエッジ
+	Edges []CollaborationEdge
+}
+
+type CollaborationEdge struct {
+	// レビューした人・返信した人
+	From string
+	// レビューされた PR の作成者・返信されたコメントを書いた人
+	To   string
+	Kind CollaborationKind
+	// レビュー・返信の回数
+	Weight int
+}
+
+type CollaborationKind string
+
+const (
+	CollaborationKindReview CollaborationKind = "review"
+	CollaborationKindReply  CollaborationKind = "reply"
+)
+
+// やりとりのグラフは PR のレビューとレビューコメントから作る
+var collaborationPullRequestFields = repository.PullRequestFields{Reviews: true, ReviewComments: true}
+
+func WrapCollaboration(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultCollaboration, error) {
+	source, err := fetchPullRequestSource(repo, cfg, collaborationPullRequestFields)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return wrapCollaboration(repo, source, reviews, cfg)
+}
+
+// 当年のやりとりのグラフだけを作る。前年の PR とレビューは取得しない
+func WrapCollaborationGraph(repo repository.GitHubRepository, cfg *config.Config) (CollaborationGraph, error) {
+	source, err := fetchPullRequestSource(repo, cfg, collaborationPullRequestFields)
+	if err != nil {
+		return CollaborationGraph{}, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return CollaborationGraph{}, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return newCollaborationGraph(source.login, source.pullRequests, withoutReviewsOfBots(reviews, cfg)), nil
+}
+
+// 当年のグラフは取得済みの source の PR と reviews から作る
+// NewCollaborators のために、アカウントを作成した年から前年までの PR とレビューを 1 年ずつ repo から取得する
+func wrapCollaboration(
+	repo repository.GitHubRepository,
+	source *pullRequestSource,
+	reviews []*repository.GivenReview,
+	cfg *config.Config,
+) (*WrappedResultCollaboration, error) {
+	login := source.login
+	graph := newCollaborationGraph(login, source.pullRequests, withoutReviewsOfBots(reviews, cfg))
+	if cfg.SkipNewCollaborators {
+		return newCollaboration(login, graph, nil, cfg), nil
+	}
+
+	previousCollaborators := map[string]bool{}
+	// 作成日時が取得できなかった場合も GitHub で PR が作れるようになった年より前はさかのぼらない
+	since := max(source.joinedAt.In(cfg.Location()).Year(), config.MinYear)
+	for year := cfg.Year() - 1; year >= since; year-- {
+		previousCfg := cfg.ForYear(year)
+		previousPullRequests, err := listFilteredPullRequests(repo, previousCfg, collaborationPullRequestFields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests of %d: %w", year, err)
+		}
+
+		previousReviews, err := repo.ListGivenReviews(previousCfg.From(), previousCfg.To())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reviews of %d: %w", year, err)
+		}
+
+		previousGraph := newCollaborationGraph(login, previousPullRequests, withoutReviewsOfBots(previousReviews, cfg))
+		for name := range previousGraph.weightsWith(login, nil) {
+			previousCollaborators[name] = true
+		}
+	}
+
+	return newCollaboration(login, graph, previousCollaborators, cfg), nil
+}
+
+// --include-bots を指定しなかった場合は bot が作成した PR へのレビューを取り除く
+func withoutReviewsOfBots(reviews []*repository.GivenReview, cfg *config.Config) []*repository.GivenReview {
+	if cfg.IncludeBots {
+		return reviews
+	}
+
+	bots := newBotMatcher(cfg)
+	return lo.Filter(reviews, func(review *repository.GivenReview, _ int) bool {
+		return !bots.isBot(review.PullRequest.Author, review.PullRequest.AuthorIsBot)
+	})
+}
+
+// previousCollaborators は前年までにやりとりがあった人。--skip-new-collaborators を指定した場合は使わない
+func newCollaboration(login string, graph CollaborationGraph, previousCollaborators map[string]bool, cfg *config.Config) *WrappedResultCollaboration {
+	return &WrappedResultCollaboration{
+		Login: login,
+		TopReviewers: pickTopNCountRankingItemDesc(
+			graph.weightsWith(login, func(edge CollaborationEdge) bool {
+				return edge.Kind == CollaborationKindReview && strings.EqualFold(edge.To, login)
+			}),
+			cfg.TopN(config.RankingTopReviewers),
+		),
+		TopDiscussionPartners: pickTopNCountRankingItemDesc(
+			graph.weightsWith(login, func(edge CollaborationEdge) bool {
+				return edge.Kind == CollaborationKindReply
+			}),
+			cfg.TopN(config.RankingTopDiscussionPartners),
+		),
+		TopCollaborators: pickTopNCountRankingItemDesc(
+			graph.weightsWith(login, nil),
+			cfg.TopN(config.RankingTopCollaborators),
+		),
+		NewCollaborators: func() []string {
+			if cfg.SkipNewCollaborators {
+				return nil
+			}
+
+			collaborators := lo.Filter(lo.Keys(graph.weightsWith(login, nil)), func(name string, _ int) bool {
+				return !previousCollaborators[name]
+			})
+			sort.Strings(collaborators)
+			return collaborators
+		}(),
+		Graph: graph,
+	}
+}
+
+// 自分の PR へのレビュー、自分がしたレビュー、自分の PR のスレッドでの返信からグラフを作る
+func newCollaborationGraph(login string, pullRequests []*repository.PullRequest, reviews []*repository.GivenReview) CollaborationGraph {
+	type edgeKey struct {
+		from, to string
+		kind     CollaborationKind
+	}
+	weights := map[edgeKey]int{}
+	add := func(from, to string, kind CollaborationKind) {
+		// 削除されたユーザー (ghost) と自分自身へのやりとりは数えない
+		if from == "" || to == "" || strings.EqualFold(from, to) {
+			return
+		}
+		weights[edgeKey{from: from, to: to, kind: kind}]++
+	}
+
+	for _, pr := range pullRequests {
+		commentAuthors := map[string]string{}
+		for _, review := range pr.Reviews {
+			if review.SubmittedAt.Valid {
+				add(review.Author, login, CollaborationKindReview)
+			}
+			for _, comment := range review.Comments {
+				commentAuthors[comment.ID] = comment.Author
+			}
+		}
+
+		for _, review := range pr.Reviews {
+			for _, comment := range review.Comments {
+				if comment.ReplyTo != "" {
+					add(comment.Author, commentAuthors[comment.ReplyTo], CollaborationKindReply)
+				}
+			}
+		}
+	}
+
+	for _, review := range reviews {
+		add(login, review.PullRequest.Author, CollaborationKindReview)
+	}
+
+	var graph CollaborationGraph
+	for key, weight := range weights {
+		graph.Edges = append(graph.Edges, CollaborationEdge{From: key.from, To: key.to, Kind: key.kind, Weight: weight})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+
+	graph.Nodes = lo.Uniq(lo.FlatMap(graph.Edges, func(edge CollaborationEdge, _ int) []string {
+		return []string{edge.From, edge.To}
+	}))
+	if len(graph.Nodes) > 0 && !lo.Contains(graph.Nodes, login) {
+		graph.Nodes = append(graph.Nodes, login)
+	}
+	sort.Strings(graph.Nodes)
+
+	return graph
+}
+
+// login と filter に一致するエッジでつながっている相手ごとの重みの合計。filter が nil の場合はすべてのエッジ
+func (g CollaborationGraph) weightsWith(login string, filter func(edge CollaborationEdge) bool) map[string]int {
+	weights := map[string]int{}
+	for _, edge := range g.Edges {
+		if filter != nil && !filter(edge) {
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(edge.From, login):
+			weights[edge.To] += edge.Weight
+		case strings.EqualFold(edge.To, login):
+			weights[edge.From] += edge.Weight
+		}
+	}
+
+	return weights
+}
@@ -0,0 +1,56 @@
+package wrapper
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func givenReviewsTo(authors ...string) []*repository.GivenReview {
+	reviews := make([]*repository.GivenReview, 0, len(authors))
+	for _, author := range authors {
+		reviews = append(reviews, &repository.GivenReview{PullRequest: repository.ReviewedPullRequest{Author: author}})
+	}
+
+	return reviews
+}
+
+func TestNewCollaboration_newCollaborators(t *testing.T) {
+	graph := newCollaborationGraph("robert", nil, givenReviewsTo("hugo", "jacob", "alice"))
+
+	tests := []struct {
+		name                  string
+		previousCollaborators map[string]bool
+		skip                  bool
+		want                  []string
+	}{
+		{
+			name: "no earlier years",
+			want: []string{"alice", "hugo", "jacob"},
+		},
+		{
+			name:                  "collaborators in any earlier year are not new",
+			previousCollaborators: map[string]bool{"hugo": true, "jacob": true, "bob": true},
+			want:                  []string{"alice"},
+		},
+		{
+			name:                  "skipped",
+			previousCollaborators: map[string]bool{"hugo": true},
+			skip:                  true,
+			want:                  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&config.Config{Top: 3, SkipNewCollaborators: tt.skip}).ForYear(2023)
+
+			got := newCollaboration("robert", graph, tt.previousCollaborators, cfg).NewCollaborators
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("NewCollaborators = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
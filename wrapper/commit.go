@@ -0,0 +1,109 @@
+package wrapper
+
+import (
+	"fmt"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+type WrappedResultCommits struct {
+	Login string
+	// 当年のコミット数 (プライベートリポジトリへのコミットを含む)
+	TotalCount int
+	// コミットしたリポジトリの数
+	RepositoryCount int
+	// リポジトリごとのコミット数
+	CommitsByRepository Ranking[CountRankingItem]
+	// 最もコミットが多かった日
+	BusiestDay DayCount
+	// PR を経由せずに直接 push したと推定されるコミットの数
+	// リポジトリのコミット数から、そのリポジトリでマージされた PR がデフォルトブランチに入れたコミット数を引いて求める
+	CommitsOutsidePullRequests int
+	// 直接 push したと推定されるコミットが多かったリポジトリ
+	DirectPushRepositories Ranking[CountRankingItem]
+}
+
+func WrapCommits(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultCommits, error) {
+	user, err := repo.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	contributions, err := repo.ListCommitContributions(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit contributions: %w", err)
+	}
+
+	// PR を経由したコミットを数えるため、フィルタを適用していない PR を使う。コミット数とマージの方法しか使わない
+	pullRequests, err := repo.ListPullRequests(cfg.From(), cfg.To(), repository.PullRequestFields{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	return wrapCommits(user.Login, contributions, pullRequests, cfg), nil
+}
+
+func wrapCommits(
+	login string,
+	contributions *repository.CommitContributions,
+	pullRequests []*repository.PullRequest,
+	cfg *config.Config,
+) *WrappedResultCommits {
+	commitsByRepository := map[string]int{}
+	commitsByDay := map[string]int{}
+	for _, contribution := range contributions.Contributions {
+		commitsByRepository[contribution.RepositoryFullName()] += contribution.CommitCount
+		// contribution はユーザーのタイムゾーンの日ごとに集計され、その日の 0 時が返ってくるので、返ってきたオフセットのまま日付を取る
+		commitsByDay[contribution.OccurredAt.Format("2006-01-02")] += contribution.CommitCount
+	}
+
+	pullRequestCommitsByRepository := map[string]int{}
+	for _, pr := range pullRequests {
+		if pr.State != repository.PullRequestStateMerged {
+			continue
+		}
+		pullRequestCommitsByRepository[pr.RepositoryFullName()] += landedCommitsCount(pr)
+	}
+
+	directPushesByRepository := map[string]int{}
+	for name, count := range commitsByRepository {
+		if direct := count - pullRequestCommitsByRepository[name]; direct > 0 {
+			directPushesByRepository[name] = direct
+		}
+	}
+
+	var busiestDay DayCount
+	for date, count := range commitsByDay {
+		// 同じコミット数の日が複数ある場合は早い日にする
+		if count > busiestDay.Count || (count == busiestDay.Count && date < busiestDay.Date) {
+			busiestDay = DayCount{Date: date, Count: count}
+		}
+	}
+
+	return &WrappedResultCommits{
+		Login:           login,
+		TotalCount:      contributions.TotalCount,
+		RepositoryCount: len(commitsByRepository),
+		CommitsByRepository: pickTopNCountRankingItemDesc(
+			commitsByRepository,
+			cfg.TopN(config.RankingCommitRepositories),
+		),
+		BusiestDay:                 busiestDay,
+		CommitsOutsidePullRequests: lo.Sum(lo.Values(directPushesByRepository)),
+		DirectPushRepositories: pickTopNCountRankingItemDesc(
+			directPushesByRepository,
+			cfg.TopN(config.RankingDirectPushRepositories),
+		),
+	}
+}
+
+// マージされた PR がデフォルトブランチに入れたコミットの数。squash でマージした PR は 1 つにまとめられる
+func landedCommitsCount(pr *repository.PullRequest) int {
+	if pr.MergeMethod == repository.PullRequestMergeMethodSquash {
+		return 1
+	}
+
+	return pr.CommitsCount
+}
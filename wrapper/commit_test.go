@@ -0,0 +1,55 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestWrapCommits_directPushes(t *testing.T) {
+	day := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	contributions := &repository.CommitContributions{
+		TotalCount: 20,
+		Contributions: []repository.CommitContribution{
+			{RepositoryOwner: "robert", RepositoryName: "squash", OccurredAt: day, CommitCount: 3},
+			{RepositoryOwner: "robert", RepositoryName: "merge", OccurredAt: day, CommitCount: 10},
+			{RepositoryOwner: "robert", RepositoryName: "rebase", OccurredAt: day, CommitCount: 7},
+		},
+	}
+	mergedPullRequest := func(repo string, method repository.PullRequestMergeMethod, commits int) *repository.PullRequest {
+		return &repository.PullRequest{
+			RepositoryOwner: "robert",
+			RepositoryName:  repo,
+			State:           repository.PullRequestStateMerged,
+			MergeMethod:     method,
+			CommitsCount:    commits,
+		}
+	}
+	pullRequests := []*repository.PullRequest{
+		// squash でマージした PR は 5 コミットあってもデフォルトブランチには 1 コミットしか入らない
+		mergedPullRequest("squash", repository.PullRequestMergeMethodSquash, 5),
+		mergedPullRequest("squash", repository.PullRequestMergeMethodSquash, 1),
+		mergedPullRequest("merge", repository.PullRequestMergeMethodMerge, 4),
+		mergedPullRequest("rebase", repository.PullRequestMergeMethodRebase, 7),
+		// マージされていない PR のコミットは引かない
+		{RepositoryOwner: "robert", RepositoryName: "merge", State: repository.PullRequestStateOpen, CommitsCount: 3},
+	}
+	cfg := (&config.Config{Top: 3}).ForYear(2023)
+
+	got := wrapCommits("robert", contributions, pullRequests, cfg)
+
+	if got.CommitsOutsidePullRequests != 7 {
+		t.Errorf("CommitsOutsidePullRequests = %d, want 7", got.CommitsOutsidePullRequests)
+	}
+	want := []CountRankingItem{{Name: "robert/merge", Count: 6}, {Name: "robert/squash", Count: 1}}
+	if len(got.DirectPushRepositories.Items) != len(want) {
+		t.Fatalf("DirectPushRepositories = %+v, want %+v", got.DirectPushRepositories.Items, want)
+	}
+	for i, w := range want {
+		if got.DirectPushRepositories.Items[i] != w {
+			t.Errorf("DirectPushRepositories[%d] = %+v, want %+v", i, got.DirectPushRepositories.Items[i], w)
+		}
+	}
+}
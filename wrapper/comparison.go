@@ -0,0 +1,106 @@
+package wrapper
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 別の年との比較
+type PullRequestComparison struct {
+	// 比較対象の年
+	Year int
+	// 数値の指標ごとの差分 (フィールドの定義順)
+	Deltas []MetricDelta
+}
+
+type MetricDelta struct {
+	// DurationStats.Percentile50 のような WrappedResultPullRequest のフィールドのパス
+	Metric string
+	Unit   MetricUnit
+	// 比較対象の年の値と今年の値。duration の場合はナノ秒
+	Previous float64
+	Current  float64
+	// Current - Previous
+	Difference float64
+	// Previous に対する変化の割合。Previous が 0 の場合は nil
+	// Previous が負の場合も、増えたときは正、減ったときは負になる
+	ChangeRate *float64
+}
+
+type MetricUnit string
+
+const (
+	MetricUnitCount    MetricUnit = "count"
+	MetricUnitRatio    MetricUnit = "ratio"
+	MetricUnitDuration MetricUnit = "duration"
+)
+
+// 比較しないフィールドにつけるタグ
+const compareTag = "compare"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// previous と current の数値のフィールドを再帰的にたどり、差分を返す
+// スライス・マップは件数が年によって変わるので比較しない。ポインタはどちらも nil でない場合だけたどる
+func comparePullRequests(previous, current *WrappedResultPullRequest, previousYear int) *PullRequestComparison {
+	return &PullRequestComparison{
+		Year:   previousYear,
+		Deltas: compareScalars(reflect.ValueOf(previous).Elem(), reflect.ValueOf(current).Elem(), nil),
+	}
+}
+
+func compareScalars(previous, current reflect.Value, path []string) []MetricDelta {
+	metric := strings.Join(path, ".")
+
+	switch {
+	case current.Type() == durationType:
+		return []MetricDelta{newMetricDelta(metric, MetricUnitDuration, float64(previous.Int()), float64(current.Int()))}
+	case current.CanInt():
+		return []MetricDelta{newMetricDelta(metric, MetricUnitCount, float64(previous.Int()), float64(current.Int()))}
+	case current.CanFloat():
+		return []MetricDelta{newMetricDelta(metric, MetricUnitRatio, previous.Float(), current.Float())}
+	}
+
+	switch current.Kind() {
+	case reflect.Pointer:
+		if previous.IsNil() || current.IsNil() {
+			return nil
+		}
+		return compareScalars(previous.Elem(), current.Elem(), path)
+	case reflect.Struct:
+		var deltas []MetricDelta
+		for i := 0; i < current.NumField(); i++ {
+			field := current.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get(compareTag) == "-" {
+				continue
+			}
+
+			deltas = append(deltas, compareScalars(
+				previous.Field(i),
+				current.Field(i),
+				append(append([]string{}, path...), field.Name),
+			)...)
+		}
+		return deltas
+	}
+
+	return nil
+}
+
+func newMetricDelta(metric string, unit MetricUnit, previous, current float64) MetricDelta {
+	delta := MetricDelta{
+		Metric:     metric,
+		Unit:       unit,
+		Previous:   previous,
+		Current:    current,
+		Difference: current - previous,
+	}
+	if previous != 0 {
+		rate := (current - previous) / math.Abs(previous)
+		delta.ChangeRate = &rate
+	}
+
+	return delta
+}
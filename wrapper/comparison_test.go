@@ -0,0 +1,170 @@
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+func TestNewMetricDelta(t *testing.T) {
+	tests := []struct {
+		name           string
+		previous       float64
+		current        float64
+		wantDifference float64
+		wantChangeRate *float64
+	}{
+		{
+			name:           "increase",
+			previous:       10,
+			current:        15,
+			wantDifference: 5,
+			wantChangeRate: lo.ToPtr(0.5),
+		},
+		{
+			name:           "decrease",
+			previous:       10,
+			current:        4,
+			wantDifference: -6,
+			wantChangeRate: lo.ToPtr(-0.6),
+		},
+		{
+			name:           "no change",
+			previous:       3,
+			current:        3,
+			wantDifference: 0,
+			wantChangeRate: lo.ToPtr(0.0),
+		},
+		{
+			name:           "zero baseline",
+			previous:       0,
+			current:        5,
+			wantDifference: 5,
+		},
+		{
+			name:           "both zero",
+			previous:       0,
+			current:        0,
+			wantDifference: 0,
+		},
+		{
+			// 相関係数などの負の値が 0 に近づいた場合も増えたとみなす
+			name:           "negative baseline increases",
+			previous:       -0.5,
+			current:        0.5,
+			wantDifference: 1,
+			wantChangeRate: lo.ToPtr(2.0),
+		},
+		{
+			name:           "negative baseline decreases",
+			previous:       -0.5,
+			current:        -1,
+			wantDifference: -0.5,
+			wantChangeRate: lo.ToPtr(-1.0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newMetricDelta("Metric", MetricUnitCount, tt.previous, tt.current)
+			if got.Previous != tt.previous || got.Current != tt.current || !almostEqual(got.Difference, tt.wantDifference) {
+				t.Errorf("newMetricDelta() = %+v, want difference %v", got, tt.wantDifference)
+			}
+			switch {
+			case (got.ChangeRate == nil) != (tt.wantChangeRate == nil):
+				t.Errorf("newMetricDelta().ChangeRate = %v, want %v", got.ChangeRate, tt.wantChangeRate)
+			case got.ChangeRate != nil && !almostEqual(*got.ChangeRate, *tt.wantChangeRate):
+				t.Errorf("newMetricDelta().ChangeRate = %v, want %v", *got.ChangeRate, *tt.wantChangeRate)
+			}
+		})
+	}
+}
+
+func TestCompareScalars(t *testing.T) {
+	type nested struct {
+		Median time.Duration
+	}
+	type result struct {
+		Count    int
+		Rate     float64
+		Lifetime time.Duration
+		Stats    nested
+		Optional *nested
+		Ignored  int `compare:"-"`
+		Items    []int
+		internal int
+	}
+
+	tests := []struct {
+		name     string
+		previous result
+		current  result
+		want     []MetricDelta
+	}{
+		{
+			name:     "scalars and nested struct in field order",
+			previous: result{Count: 2, Rate: 0.5, Lifetime: time.Hour, Stats: nested{Median: time.Hour}, Ignored: 1, Items: []int{1}, internal: 1},
+			current:  result{Count: 1, Rate: 0.5, Lifetime: 3 * time.Hour, Stats: nested{Median: 0}, Ignored: 2, Items: []int{1, 2}, internal: 2},
+			want: []MetricDelta{
+				{Metric: "Count", Unit: MetricUnitCount, Previous: 2, Current: 1, Difference: -1, ChangeRate: lo.ToPtr(-0.5)},
+				{Metric: "Rate", Unit: MetricUnitRatio, Previous: 0.5, Current: 0.5, Difference: 0, ChangeRate: lo.ToPtr(0.0)},
+				{Metric: "Lifetime", Unit: MetricUnitDuration, Previous: float64(time.Hour), Current: float64(3 * time.Hour), Difference: float64(2 * time.Hour), ChangeRate: lo.ToPtr(2.0)},
+				{Metric: "Stats.Median", Unit: MetricUnitDuration, Previous: float64(time.Hour), Current: 0, Difference: -float64(time.Hour), ChangeRate: lo.ToPtr(-1.0)},
+			},
+		},
+		{
+			name:     "zero baseline",
+			previous: result{},
+			current:  result{Count: 3},
+			want: []MetricDelta{
+				{Metric: "Count", Unit: MetricUnitCount, Previous: 0, Current: 3, Difference: 3},
+				{Metric: "Rate", Unit: MetricUnitRatio},
+				{Metric: "Lifetime", Unit: MetricUnitDuration},
+				{Metric: "Stats.Median", Unit: MetricUnitDuration},
+			},
+		},
+		{
+			name:     "pointer in both years",
+			previous: result{Optional: &nested{Median: time.Hour}},
+			current:  result{Optional: &nested{Median: time.Hour}},
+			want: []MetricDelta{
+				{Metric: "Count", Unit: MetricUnitCount},
+				{Metric: "Rate", Unit: MetricUnitRatio},
+				{Metric: "Lifetime", Unit: MetricUnitDuration},
+				{Metric: "Stats.Median", Unit: MetricUnitDuration},
+				{Metric: "Optional.Median", Unit: MetricUnitDuration, Previous: float64(time.Hour), Current: float64(time.Hour), ChangeRate: lo.ToPtr(0.0)},
+			},
+		},
+		{
+			name:     "missing in the previous year",
+			previous: result{},
+			current:  result{Optional: &nested{Median: time.Hour}},
+			want: []MetricDelta{
+				{Metric: "Count", Unit: MetricUnitCount},
+				{Metric: "Rate", Unit: MetricUnitRatio},
+				{Metric: "Lifetime", Unit: MetricUnitDuration},
+				{Metric: "Stats.Median", Unit: MetricUnitDuration},
+			},
+		},
+		{
+			name:     "missing in the current year",
+			previous: result{Optional: &nested{Median: time.Hour}},
+			current:  result{},
+			want: []MetricDelta{
+				{Metric: "Count", Unit: MetricUnitCount},
+				{Metric: "Rate", Unit: MetricUnitRatio},
+				{Metric: "Lifetime", Unit: MetricUnitDuration},
+				{Metric: "Stats.Median", Unit: MetricUnitDuration},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareScalars(reflect.ValueOf(tt.previous), reflect.ValueOf(tt.current), nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compareScalars() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+type PullRequestDraftStats struct {
+	// draft として作成された PR の数
+	BornAsDraftCount int
+	// Ready for review になった後に draft に戻された PR の数
+	ConvertedToDraftCount int
+	// クローズ・マージ済みの PR が draft だった時間 (draft だったことがある PR のみ)
+	DraftDuration PullRequestDuration
+	// クローズ・マージ済みの PR がレビュー可能だった時間
+	ReadyDuration PullRequestDuration
+	// クローズ・マージ済みの PR の作成 ~ クローズまでの時間のうち、draft だった時間の割合 (0 ~ 1)
+	DraftTimeShare float64
+}
+
+var draftTimelineEventTypes = []string{
+	repository.TimelineEventReadyForReview,
+	repository.TimelineEventConvertToDraft,
+}
+
+// draft として作成されたかどうか
+// 最初の draft の切り替えが Ready for review であれば draft で作成されている
+func isBornAsDraft(pr *repository.PullRequest) bool {
+	event, found := lo.Find(pr.TimelineEvents, func(event repository.PullRequestTimelineEvent) bool {
+		return lo.Contains(draftTimelineEventTypes, event.Type)
+	})
+	if !found {
+		return pr.IsDraft
+	}
+
+	return event.Type == repository.TimelineEventReadyForReview
+}
+
+// 最初に Ready for review になった日時。draft で作成されていなければ作成日時
+// 作成後に draft に戻してから Ready for review にした場合も作成日時を返す。draft のままの PR も作成日時を返す
+func readyForReviewAt(pr *repository.PullRequest) time.Time {
+	if !isBornAsDraft(pr) {
+		return pr.CreatedAt
+	}
+
+	if event, found := lo.Find(pr.TimelineEvents, func(event repository.PullRequestTimelineEvent) bool {
+		return event.Type == repository.TimelineEventReadyForReview
+	}); found {
+		return event.CreatedAt
+	}
+
+	return pr.CreatedAt
+}
+
+// マージされた PR の、lifetimeFrom の時点からマージまでの期間
+func pullRequestLifetime(pr *repository.PullRequest, lifetimeFrom string) timeSpan {
+	start := pr.CreatedAt
+	if lifetimeFrom == config.LifetimeFromReady {
+		start = readyForReviewAt(pr)
+	}
+
+	return timeSpan{from: start, to: pr.MergedAt.Time}
+}
+
+// クローズ・マージ済みの PR が draft だった期間と、作成 ~ クローズまでの期間を返す
+// クローズされていない PR は false
+func draftSpans(pr *repository.PullRequest) (drafts []timeSpan, lifetime timeSpan, ok bool) {
+	closedAt := pr.ClosedAt
+	if pr.MergedAt.Valid {
+		closedAt = pr.MergedAt
+	}
+	if !closedAt.Valid || closedAt.Time.Before(pr.CreatedAt) {
+		return nil, timeSpan{}, false
+	}
+
+	drafting := isBornAsDraft(pr)
+	since := pr.CreatedAt
+	for _, event := range pr.TimelineEvents {
+		if event.CreatedAt.After(closedAt.Time) {
+			break
+		}
+
+		switch {
+		case event.Type == repository.TimelineEventReadyForReview && drafting:
+			drafts = append(drafts, timeSpan{from: since, to: event.CreatedAt})
+			drafting = false
+		case event.Type == repository.TimelineEventConvertToDraft && !drafting:
+			since = event.CreatedAt
+			drafting = true
+		}
+	}
+	if drafting {
+		drafts = append(drafts, timeSpan{from: since, to: closedAt.Time})
+	}
+
+	return drafts, timeSpan{from: pr.CreatedAt, to: closedAt.Time}, true
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"drafts",
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &draftStatsAccumulator{calendar: dataset.Config.BusinessHours}
+		},
+	))
+}
+
+type draftStatsAccumulator struct {
+	calendar              *businesshours.Calendar
+	bornAsDraftCount      int
+	convertedToDraftCount int
+	// draft だった時間 (draft だったことがある PR のみ) とレビュー可能だった時間
+	draftDurations, readyDurations durationSketch
+	// 営業時間だけで数えた draftDurations と readyDurations。business_hours を設定していない場合は使わない
+	businessDraftDurations, businessReadyDurations durationSketch
+}
+
+func (a *draftStatsAccumulator) Add(pr *repository.PullRequest) {
+	if isBornAsDraft(pr) {
+		a.bornAsDraftCount++
+	}
+	if lo.ContainsBy(pr.TimelineEvents, func(event repository.PullRequestTimelineEvent) bool {
+		return event.Type == repository.TimelineEventConvertToDraft
+	}) {
+		a.convertedToDraftCount++
+	}
+
+	drafts, lifetime, ok := draftSpans(pr)
+	if !ok {
+		return
+	}
+
+	// 期間ごとの経過時間を d で数えた、draft だった時間とレビュー可能だった時間を足す
+	add := func(d func(s timeSpan) time.Duration, draftDurations, readyDurations *durationSketch) {
+		draft := lo.SumBy(drafts, d)
+		if draft > 0 {
+			draftDurations.add(draft)
+		}
+		readyDurations.add(d(lifetime) - draft)
+	}
+	add(timeSpan.duration, &a.draftDurations, &a.readyDurations)
+	if a.calendar != nil {
+		add(func(s timeSpan) time.Duration {
+			return a.calendar.Duration(s.from, s.to)
+		}, &a.businessDraftDurations, &a.businessReadyDurations)
+	}
+}
+
+func (a *draftStatsAccumulator) Result() any {
+	stats := PullRequestDraftStats{
+		BornAsDraftCount:      a.bornAsDraftCount,
+		ConvertedToDraftCount: a.convertedToDraftCount,
+		DraftDuration:         a.draftDurations.stats(),
+		ReadyDuration:         a.readyDurations.stats(),
+	}
+	if totalLifetime := a.draftDurations.sum + a.readyDurations.sum; totalLifetime > 0 {
+		stats.DraftTimeShare = float64(a.draftDurations.sum) / float64(totalLifetime)
+	}
+
+	if a.calendar != nil {
+		stats.DraftDuration.BusinessHours = lo.ToPtr(a.businessDraftDurations.stats())
+		stats.ReadyDuration.BusinessHours = lo.ToPtr(a.businessReadyDurations.stats())
+	}
+
+	return stats
+}
@@ -0,0 +1,56 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestReadyForReviewAt(t *testing.T) {
+	createdAt := time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+	event := func(typ string, hours int) repository.PullRequestTimelineEvent {
+		return repository.PullRequestTimelineEvent{Type: typ, CreatedAt: createdAt.Add(time.Duration(hours) * time.Hour)}
+	}
+
+	tests := []struct {
+		name string
+		pr   *repository.PullRequest
+		want time.Time
+	}{
+		{
+			name: "created ready",
+			pr:   &repository.PullRequest{CreatedAt: createdAt},
+			want: createdAt,
+		},
+		{
+			name: "created as draft",
+			pr: &repository.PullRequest{CreatedAt: createdAt, TimelineEvents: []repository.PullRequestTimelineEvent{
+				event(repository.TimelineEventReadyForReview, 2),
+				event(repository.TimelineEventConvertToDraft, 3),
+				event(repository.TimelineEventReadyForReview, 5),
+			}},
+			want: createdAt.Add(2 * time.Hour),
+		},
+		{
+			name: "created ready and converted to draft later",
+			pr: &repository.PullRequest{CreatedAt: createdAt, TimelineEvents: []repository.PullRequestTimelineEvent{
+				event(repository.TimelineEventConvertToDraft, 1),
+				event(repository.TimelineEventReadyForReview, 4),
+			}},
+			want: createdAt,
+		},
+		{
+			name: "still draft",
+			pr:   &repository.PullRequest{CreatedAt: createdAt, IsDraft: true},
+			want: createdAt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readyForReviewAt(tt.pr); !got.Equal(tt.want) {
+				t.Errorf("readyForReviewAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 経過時間を測る期間
+type timeSpan struct {
+	from time.Time
+	to   time.Time
+}
+
+func (s timeSpan) duration() time.Duration {
+	return s.to.Sub(s.from)
+}
+
+// durations の統計値を返す。空の場合はゼロ値
+func durationStats(durations []time.Duration) PullRequestDuration {
+	var sketch durationSketch
+	for _, d := range durations {
+		sketch.add(d)
+	}
+
+	return sketch.stats()
+}
+
+// spans の経過時間の統計値を返す。calendar が nil でなければ営業時間だけで数えた統計値もつける
+func spanStats(spans []timeSpan, calendar *businesshours.Calendar) PullRequestDuration {
+	result := durationStats(lo.Map(spans, func(s timeSpan, _ int) time.Duration {
+		return s.duration()
+	}))
+	result.BusinessHours = businessSpanStats(spans, calendar)
+
+	return result
+}
+
+// 営業時間だけで数えた spans の統計値。calendar が nil の場合は nil
+func businessSpanStats(spans []timeSpan, calendar *businesshours.Calendar) *PullRequestDuration {
+	if calendar == nil {
+		return nil
+	}
+
+	return lo.ToPtr(durationStats(lo.Map(spans, func(s timeSpan, _ int) time.Duration {
+		return calendar.Duration(s.from, s.to)
+	})))
+}
+
+// 期間を 1 件ずつ受け取って経過時間の統計値を計算する。calendar が nil でなければ営業時間だけで数えた統計値もつける
+type spanSketch struct {
+	calendar          *businesshours.Calendar
+	durations         durationSketch
+	businessDurations durationSketch
+}
+
+func newSpanSketch(calendar *businesshours.Calendar) *spanSketch {
+	return &spanSketch{calendar: calendar}
+}
+
+func (s *spanSketch) add(span timeSpan) {
+	s.durations.add(span.duration())
+	if s.calendar != nil {
+		s.businessDurations.add(s.calendar.Duration(span.from, span.to))
+	}
+}
+
+// 受け取った期間の統計値。1 件も受け取っていない場合はゼロ値 (calendar があれば BusinessHours もゼロ値)
+func (s *spanSketch) stats() PullRequestDuration {
+	result := s.durations.stats()
+	if s.calendar != nil {
+		result.BusinessHours = lo.ToPtr(s.businessDurations.stats())
+	}
+
+	return result
+}
+
+func newPullRequestDurationItem(pr *repository.PullRequest, span timeSpan, calendar *businesshours.Calendar) PullRequestDurationItem {
+	item := PullRequestDurationItem{
+		PullRequest: toSimplePullRequest(pr),
+		Duration:    span.duration(),
+	}
+	if calendar != nil {
+		item.BusinessDuration = lo.ToPtr(calendar.Duration(span.from, span.to))
+	}
+
+	return item
+}
@@ -0,0 +1,114 @@
+package wrapper
+
+import (
+	"fmt"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+type WrappedResultIssues struct {
+	Login string
+	// 当年に作成した issue の数
+	OpenedCount int
+	// そのうちクローズされた issue の数
+	ClosedCount int
+	// そのうちまだ OPEN の issue の数
+	StillOpenCount int
+	// 作成 ~ クローズまでの時間
+	TimeToClose PullRequestDuration
+	// コメントが最も多くつけられた issue
+	MostCommentedIssues Ranking[IssueRankingItem]
+	// ラベルごとの issue の数 (多い順)
+	LabelDistribution []CountRankingItem
+	// issue を作成した数が多かったリポジトリ
+	TopRepositories Ranking[CountRankingItem]
+}
+
+type IssueRankingItem struct {
+	Issue SimpleIssue
+	Count int
+}
+
+type SimpleIssue struct {
+	Title  string
+	Owner  string
+	Repo   string
+	Number int
+	URL    string
+}
+
+func WrapIssues(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultIssues, error) {
+	user, err := repo.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	issues, err := repo.ListIssues(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	return wrapIssues(user.Login, issues, cfg), nil
+}
+
+func wrapIssues(login string, issues []*repository.Issue, cfg *config.Config) *WrappedResultIssues {
+	closedIssues := lo.Filter(issues, func(issue *repository.Issue, _ int) bool {
+		return issue.State == repository.IssueStateClosed && issue.ClosedAt.Valid
+	})
+
+	labelCounts := map[string]int{}
+	for _, issue := range issues {
+		for _, label := range lo.Uniq(issue.Labels) {
+			labelCounts[label]++
+		}
+	}
+	labelDistribution := pickTopNCountRankingItemDesc(labelCounts, lo.Max([]int{len(labelCounts), 1})).Items
+
+	return &WrappedResultIssues{
+		Login:          login,
+		OpenedCount:    len(issues),
+		ClosedCount:    len(closedIssues),
+		StillOpenCount: len(issues) - len(closedIssues),
+		TimeToClose: spanStats(lo.Map(closedIssues, func(issue *repository.Issue, _ int) timeSpan {
+			return timeSpan{from: issue.CreatedAt, to: issue.ClosedAt.Time}
+		}), cfg.BusinessHours),
+		MostCommentedIssues: pickTopNRankingItemDesc(
+			issues,
+			cfg.TopN(config.RankingMostCommentedIssues),
+			func(issue *repository.Issue) int {
+				return issue.CommentsCount
+			},
+			lessIssueForTie,
+			func(issue *repository.Issue, count int) IssueRankingItem {
+				return IssueRankingItem{
+					Issue: SimpleIssue{
+						Title:  issue.Title,
+						Owner:  issue.RepositoryOwner,
+						Repo:   issue.RepositoryName,
+						Number: issue.Number,
+						URL:    issue.URL,
+					},
+					Count: count,
+				}
+			},
+		),
+		LabelDistribution: labelDistribution,
+		TopRepositories: pickTopNCountRankingItemDesc(
+			lo.CountValuesBy(issues, func(issue *repository.Issue) string {
+				return issue.RepositoryFullName()
+			}),
+			cfg.TopN(config.RankingIssueRepositories),
+		),
+	}
+}
+
+// 同順位の issue を並べる順番。作成日時が古い順、同じなら URL の辞書順
+func lessIssueForTie(a, b *repository.Issue) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+
+	return a.URL < b.URL
+}
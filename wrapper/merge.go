@@ -0,0 +1,114 @@
+package wrapper
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 誰がどのようにマージしたか
+type PullRequestMergeStats struct {
+	// マージされた PR の数
+	MergedCount int
+	// 作成者以外からの承認がないままマージされた PR の数。bot の承認も承認として数える
+	UnapprovedMergedCount int
+	// MergedCount のうち UnapprovedMergedCount の割合 (0 ~ 1)
+	UnapprovedMergeRate float64
+	// 自分でマージした PR の数
+	SelfMergedCount int
+	// MergedCount のうち SelfMergedCount の割合 (0 ~ 1)
+	SelfMergeRate float64
+	// 自分の PR をマージした回数が多かった人 (自分を除く)
+	TopMergers Ranking[CountRankingItem]
+	// リポジトリごとの内訳 (リポジトリ名順)
+	Repositories []RepositoryMergeStats
+}
+
+type RepositoryMergeStats struct {
+	// owner/repo
+	Repository            string
+	MergedCount           int
+	UnapprovedMergedCount int
+	SelfMergedCount       int
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"merges",
+		[]MetricData{MetricDataPullRequests, MetricDataReviews},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &mergeStatsAccumulator{
+				dataset:      dataset,
+				login:        dataset.Login,
+				n:            dataset.Config.TopN(config.RankingTopMergers),
+				mergers:      map[string]int{},
+				repositories: map[string]*RepositoryMergeStats{},
+			}
+		},
+	))
+}
+
+type mergeStatsAccumulator struct {
+	dataset *PullRequestDataset
+	login   string
+	// TopMergers の件数
+	n     int
+	stats PullRequestMergeStats
+	// 自分以外のマージした人ごとのマージした回数
+	mergers      map[string]int
+	repositories map[string]*RepositoryMergeStats
+}
+
+func (a *mergeStatsAccumulator) Add(pr *repository.PullRequest) {
+	if !isPullRequestMerged(pr) {
+		return
+	}
+
+	name := pr.RepositoryFullName()
+	repo, ok := a.repositories[name]
+	if !ok {
+		repo = &RepositoryMergeStats{Repository: name}
+		a.repositories[name] = repo
+	}
+
+	a.stats.MergedCount++
+	repo.MergedCount++
+	// bot だけが承認した PR も承認されたとみなすため、bot のレビューを取り除く前のレビューで確かめる
+	withBots := *pr
+	withBots.Reviews = a.dataset.ReviewsWithBots(pr)
+	if !lo.ContainsBy(reviewsBeforeMerge(a.login, &withBots), func(review repository.PullRequestReview) bool {
+		return review.State == reviewStateApproved
+	}) {
+		a.stats.UnapprovedMergedCount++
+		repo.UnapprovedMergedCount++
+	}
+	switch {
+	case strings.EqualFold(pr.MergedBy, a.login):
+		a.stats.SelfMergedCount++
+		repo.SelfMergedCount++
+	// 削除されたユーザー (ghost) がマージした PR は数えない
+	case pr.MergedBy != "":
+		a.mergers[pr.MergedBy]++
+	}
+}
+
+func (a *mergeStatsAccumulator) Result() any {
+	stats := a.stats
+	stats.TopMergers = pickTopNCountRankingItemDesc(a.mergers, a.n)
+	if stats.MergedCount > 0 {
+		stats.UnapprovedMergeRate = float64(stats.UnapprovedMergedCount) / float64(stats.MergedCount)
+		stats.SelfMergeRate = float64(stats.SelfMergedCount) / float64(stats.MergedCount)
+	}
+
+	for _, repo := range a.repositories {
+		stats.Repositories = append(stats.Repositories, *repo)
+	}
+	sort.Slice(stats.Repositories, func(i, j int) bool {
+		return stats.Repositories[i].Repository < stats.Repositories[j].Repository
+	})
+
+	return stats
+}
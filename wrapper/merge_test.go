@@ -0,0 +1,103 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestMergeStatsAccumulator_unapprovedMerges(t *testing.T) {
+	createdAt := time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+	mergedAt := createdAt.Add(24 * time.Hour)
+	review := func(author string, isBot bool, state string, submittedAt time.Time) repository.PullRequestReview {
+		return repository.PullRequestReview{Author: author, AuthorIsBot: isBot, State: state, SubmittedAt: null.TimeFrom(submittedAt)}
+	}
+	mergedPullRequest := func(id string, reviews ...repository.PullRequestReview) *repository.PullRequest {
+		return &repository.PullRequest{
+			ID:              id,
+			RepositoryOwner: "robert",
+			RepositoryName:  "app",
+			State:           repository.PullRequestStateMerged,
+			CreatedAt:       createdAt,
+			MergedAt:        null.TimeFrom(mergedAt),
+			MergedBy:        "robert",
+			Reviews:         reviews,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		pr           *repository.PullRequest
+		wantApproved bool
+	}{
+		{
+			name:         "approved by a human",
+			pr:           mergedPullRequest("human", review("hugo", false, reviewStateApproved, createdAt.Add(time.Hour))),
+			wantApproved: true,
+		},
+		{
+			name:         "approved only by a bot",
+			pr:           mergedPullRequest("bot", review("approver[bot]", true, reviewStateApproved, createdAt.Add(time.Hour))),
+			wantApproved: true,
+		},
+		{
+			name: "approved only by the author",
+			pr:   mergedPullRequest("self", review("robert", false, reviewStateApproved, createdAt.Add(time.Hour))),
+		},
+		{
+			name: "approved after the merge",
+			pr:   mergedPullRequest("late", review("hugo", false, reviewStateApproved, mergedAt.Add(time.Hour))),
+		},
+		{
+			name: "changes requested",
+			pr:   mergedPullRequest("changes", review("hugo", false, reviewStateChangesRequested, createdAt.Add(time.Hour))),
+		},
+		{
+			name: "no reviews",
+			pr:   mergedPullRequest("none"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&config.Config{Top: 3}).ForYear(2023)
+			pullRequestsWithBots := []*repository.PullRequest{tt.pr}
+			// 集計には --include-bots を指定しなかった場合と同じく bot のレビューを取り除いた PR を渡す
+			pullRequests := withoutExcludedBotActivity(pullRequestsWithBots, cfg)
+			dataset := newPullRequestDataset("robert", pullRequests, pullRequestsWithBots, cfg, mergedAt)
+
+			accumulator := registeredPullRequestMetric(t, "merges").(StreamingMetric).NewAccumulator(dataset)
+			for _, pr := range pullRequests {
+				accumulator.Add(pr)
+			}
+			got := accumulator.Result().(PullRequestMergeStats)
+
+			wantUnapproved := 1
+			if tt.wantApproved {
+				wantUnapproved = 0
+			}
+			if got.MergedCount != 1 || got.UnapprovedMergedCount != wantUnapproved {
+				t.Errorf("MergedCount, UnapprovedMergedCount = %d, %d, want 1, %d", got.MergedCount, got.UnapprovedMergedCount, wantUnapproved)
+			}
+			if got.SelfMergedCount != 1 {
+				t.Errorf("SelfMergedCount = %d, want 1", got.SelfMergedCount)
+			}
+		})
+	}
+}
+
+func registeredPullRequestMetric(t *testing.T, name string) Metric {
+	t.Helper()
+
+	for _, metric := range PullRequestMetrics() {
+		if metric.Name() == name {
+			return metric
+		}
+	}
+	t.Fatalf("metric %q is not registered", name)
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package wrapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// PR の指標を計算するときに渡すデータ
+type PullRequestDataset struct {
+	Login string
+	// フィルタを適用した PR
+	PullRequests []*repository.PullRequest
+	// PullRequests のうちマージされた PR
+	MergedPullRequests []*repository.PullRequest
+	Config             *config.Config
+	// OPEN の PR の経過時間を数える時点
+	Now time.Time
+	// --include-bots を指定しなかった場合に取り除く前の、PR の ID ごとのレビュー
+	reviewsWithBots map[string][]repository.PullRequestReview
+}
+
+// bot のレビューも含めた pr のレビュー。承認のように bot がしたものも数えたい場合に使う
+func (d *PullRequestDataset) ReviewsWithBots(pr *repository.PullRequest) []repository.PullRequestReview {
+	if reviews, ok := d.reviewsWithBots[pr.ID]; ok {
+		return reviews
+	}
+
+	return pr.Reviews
+}
+
+// 指標の計算に使う PR のデータ。有効な指標が必要としないデータは API から取得しない
+type MetricData string
+
+const (
+	// 作成・マージ日時や変更行数などの PR 自体のデータ。常に取得する
+	MetricDataPullRequests MetricData = "pull_requests"
+	// レビュー (レビューコメントは含まない)
+	MetricDataReviews MetricData = "reviews"
+	// レビューコメント。取得する場合はレビューも取得する
+	MetricDataReviewComments MetricData = "review_comments"
+	// ready for review などのタイムラインのイベント
+	MetricDataTimeline MetricData = "timeline"
+)
+
+// 指標の計算に使うデータから、PR と一緒に取得する項目を決める
+func pullRequestFieldsFor(metrics []Metric) repository.PullRequestFields {
+	requires := lo.FlatMap(metrics, func(m Metric, _ int) []MetricData {
+		return m.Requires()
+	})
+
+	return repository.PullRequestFields{
+		Reviews:        lo.Contains(requires, MetricDataReviews) || lo.Contains(requires, MetricDataReviewComments),
+		ReviewComments: lo.Contains(requires, MetricDataReviewComments),
+		TimelineEvents: lo.Contains(requires, MetricDataTimeline),
+	}
+}
+
+// PR の集計結果の指標
+type Metric interface {
+	// --disable-metric で指定する名前
+	// WrappedResultPullRequest に同じ名前の metric タグのフィールドがある場合はそこに結果を入れ、ない場合は Metrics に入れる
+	Name() string
+	// 計算に使うデータ。ここにないデータは取得されず、PR の該当する項目は空になる
+	Requires() []MetricData
+	Compute(dataset *PullRequestDataset) any
+}
+
+// PR を 1 件ずつ受け取って計算する指標
+// wrapPullRequests は PR を 1 回だけたどり、有効なすべての StreamingMetric の MetricAccumulator に同時に渡す
+type StreamingMetric interface {
+	Metric
+	NewAccumulator(dataset *PullRequestDataset) MetricAccumulator
+}
+
+// StreamingMetric の集計途中の状態
+type MetricAccumulator interface {
+	Add(pr *repository.PullRequest)
+	Result() any
+}
+
+// 指標を書いたフィールドにつけるタグ
+const metricTag = "metric"
+
+type pullRequestMetric struct {
+	name     string
+	requires []MetricData
+	compute  func(dataset *PullRequestDataset) any
+}
+
+func (m pullRequestMetric) Name() string {
+	return m.name
+}
+
+func (m pullRequestMetric) Requires() []MetricData {
+	return m.requires
+}
+
+func (m pullRequestMetric) Compute(dataset *PullRequestDataset) any {
+	return m.compute(dataset)
+}
+
+// 関数から Metric を作る
+func NewPullRequestMetric(name string, requires []MetricData, compute func(dataset *PullRequestDataset) any) Metric {
+	return pullRequestMetric{name: name, requires: requires, compute: compute}
+}
+
+type streamingPullRequestMetric struct {
+	name           string
+	requires       []MetricData
+	newAccumulator func(dataset *PullRequestDataset) MetricAccumulator
+}
+
+func (m streamingPullRequestMetric) Name() string {
+	return m.name
+}
+
+func (m streamingPullRequestMetric) Requires() []MetricData {
+	return m.requires
+}
+
+func (m streamingPullRequestMetric) NewAccumulator(dataset *PullRequestDataset) MetricAccumulator {
+	return m.newAccumulator(dataset)
+}
+
+func (m streamingPullRequestMetric) Compute(dataset *PullRequestDataset) any {
+	accumulator := m.newAccumulator(dataset)
+	for _, pr := range dataset.PullRequests {
+		accumulator.Add(pr)
+	}
+
+	return accumulator.Result()
+}
+
+// MetricAccumulator を作る関数から StreamingMetric を作る
+func NewStreamingPullRequestMetric(
+	name string,
+	requires []MetricData,
+	newAccumulator func(dataset *PullRequestDataset) MetricAccumulator,
+) StreamingMetric {
+	return streamingPullRequestMetric{name: name, requires: requires, newAccumulator: newAccumulator}
+}
+
+var pullRequestMetrics []Metric
+
+// PR の指標を登録する。集計は登録した順に行う
+// 同じ名前の指標を登録した場合は panic する
+func RegisterPullRequestMetric(metric Metric) {
+	if lo.ContainsBy(pullRequestMetrics, func(m Metric) bool { return m.Name() == metric.Name() }) {
+		panic("duplicate pull request metric: " + metric.Name())
+	}
+
+	pullRequestMetrics = append(pullRequestMetrics, metric)
+}
+
+// 登録されている PR の指標 (登録順)
+func PullRequestMetrics() []Metric {
+	return append([]Metric{}, pullRequestMetrics...)
+}
+
+// 設定で無効にされていない PR の指標を返す。存在しない名前が指定されている場合はエラー
+func EnabledPullRequestMetrics(cfg *config.Config) ([]Metric, error) {
+	names := lo.Map(pullRequestMetrics, func(m Metric, _ int) string { return m.Name() })
+	for _, name := range cfg.DisabledMetrics {
+		if !lo.Contains(names, name) {
+			sorted := append([]string{}, names...)
+			sort.Strings(sorted)
+			return nil, fmt.Errorf("unknown metric %q, must be one of %s", name, strings.Join(sorted, ", "))
+		}
+	}
+
+	return lo.Filter(pullRequestMetrics, func(m Metric, _ int) bool {
+		return !lo.Contains(cfg.DisabledMetrics, m.Name())
+	}), nil
+}
+
+// 指標の結果を metric タグが name のフィールドに入れる。該当するフィールドがない場合は Metrics に入れる
+// フィールドは value と同じ型か、value の型を要素とするポインタ
+func (r *WrappedResultPullRequest) setMetric(name string, value any) {
+	v := reflect.ValueOf(r).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get(metricTag) != name {
+			continue
+		}
+
+		field := v.Field(i)
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.Type() == field.Type():
+			field.Set(rv)
+		case rv.Type() == field.Type().Elem():
+			ptr := reflect.New(rv.Type())
+			ptr.Elem().Set(rv)
+			field.Set(ptr)
+		default:
+			panic(fmt.Sprintf("metric %s returned %s, but the field is %s", name, rv.Type(), field.Type()))
+		}
+		return
+	}
+
+	if r.Metrics == nil {
+		r.Metrics = map[string]any{}
+	}
+	r.Metrics[name] = value
+}
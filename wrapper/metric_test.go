@@ -0,0 +1,58 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestPullRequestFieldsFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled []string
+		want     repository.PullRequestFields
+	}{
+		{
+			name: "all metrics",
+			want: repository.AllPullRequestFields(),
+		},
+		{
+			name:     "no metrics use reviews",
+			disabled: []string{"most_reviewed_by", config.RankingMostCommentedPullRequests, "merges", "review_phases"},
+			want:     repository.PullRequestFields{TimelineEvents: true},
+		},
+		{
+			name:     "reviews without comments",
+			disabled: []string{config.RankingMostCommentedPullRequests},
+			want:     repository.PullRequestFields{Reviews: true, TimelineEvents: true},
+		},
+		{
+			name: "no metrics use timeline events",
+			disabled: []string{
+				config.RankingShortLivePullRequests,
+				config.RankingLongLivePullRequests,
+				"duration_stats",
+				"size",
+				"drafts",
+				"review_phases",
+				"survival",
+				"trends",
+			},
+			want: repository.PullRequestFields{Reviews: true, ReviewComments: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := EnabledPullRequestMetrics(&config.Config{DisabledMetrics: tt.disabled})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pullRequestFieldsFor(metrics); got != tt.want {
+				t.Errorf("pullRequestFieldsFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package wrapper
+
+import (
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+type OrganizationSectionKind string
+
+const (
+	// ユーザーが所属している Organization のリポジトリ
+	OrganizationSectionKindOrganization OrganizationSectionKind = "organization"
+	// ユーザー自身がオーナーのリポジトリ
+	OrganizationSectionKindPersonal OrganizationSectionKind = "personal"
+	// 上記以外 (所属していない Organization や他のユーザー) のリポジトリ
+	OrganizationSectionKindOtherOSS OrganizationSectionKind = "other OSS"
+)
+
+type OrganizationSection struct {
+	// Organization の login。personal / other OSS の場合は Kind と同じ値
+	Name   string
+	Kind   OrganizationSectionKind
+	Result *WrappedResultPullRequest
+}
+
+type pullRequestGroup struct {
+	name         string
+	kind         OrganizationSectionKind
+	pullRequests []*repository.PullRequest
+}
+
+// PR をリポジトリのオーナーで Organization ごとに振り分ける
+// 所属している Organization は PR が 0 件でも返す。personal と other OSS は末尾に置く
+func groupPullRequestsByOrganization(
+	login string,
+	organizations []*repository.Organization,
+	pullRequests []*repository.PullRequest,
+) []pullRequestGroup {
+	groups := lo.Map(organizations, func(org *repository.Organization, _ int) pullRequestGroup {
+		return pullRequestGroup{
+			name: org.Login,
+			kind: OrganizationSectionKindOrganization,
+		}
+	})
+	personal := pullRequestGroup{
+		name: string(OrganizationSectionKindPersonal),
+		kind: OrganizationSectionKindPersonal,
+	}
+	otherOSS := pullRequestGroup{
+		name: string(OrganizationSectionKindOtherOSS),
+		kind: OrganizationSectionKindOtherOSS,
+	}
+
+	for _, pr := range pullRequests {
+		// GitHub の login は大文字小文字を区別しない
+		if strings.EqualFold(pr.RepositoryOwner, login) {
+			personal.pullRequests = append(personal.pullRequests, pr)
+			continue
+		}
+
+		_, i, found := lo.FindIndexOf(groups, func(group pullRequestGroup) bool {
+			return strings.EqualFold(group.name, pr.RepositoryOwner)
+		})
+		if !found {
+			otherOSS.pullRequests = append(otherOSS.pullRequests, pr)
+			continue
+		}
+
+		groups[i].pullRequests = append(groups[i].pullRequests, pr)
+	}
+
+	return append(groups, personal, otherOSS)
+}
@@ -0,0 +1,61 @@
+package wrapper
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+func TestGroupPullRequestsByOrganization(t *testing.T) {
+	organizations := []*repository.Organization{{Login: "acme"}, {Login: "empty-org"}}
+	pullRequest := func(number int, owner string) *repository.PullRequest {
+		return &repository.PullRequest{Number: number, RepositoryOwner: owner, RepositoryName: "repo"}
+	}
+	pullRequests := []*repository.PullRequest{
+		pullRequest(1, "acme"),
+		// オーナー名は大文字小文字を区別しない
+		pullRequest(2, "ACME"),
+		pullRequest(3, "Robert"),
+		pullRequest(4, "golang"),
+		pullRequest(5, "robert"),
+	}
+
+	got := groupPullRequestsByOrganization("robert", organizations, pullRequests)
+
+	type group struct {
+		name    string
+		kind    OrganizationSectionKind
+		numbers []int
+	}
+	want := []group{
+		{name: "acme", kind: OrganizationSectionKindOrganization, numbers: []int{1, 2}},
+		// PR がない所属 Organization も返す
+		{name: "empty-org", kind: OrganizationSectionKindOrganization},
+		{name: "personal", kind: OrganizationSectionKindPersonal, numbers: []int{3, 5}},
+		{name: "other OSS", kind: OrganizationSectionKindOtherOSS, numbers: []int{4}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		numbers := lo.Map(got[i].pullRequests, func(pr *repository.PullRequest, _ int) int {
+			return pr.Number
+		})
+		if got[i].name != w.name || got[i].kind != w.kind || !slices.Equal(numbers, w.numbers) {
+			t.Errorf("groups[%d] = %s (%s) %v, want %s (%s) %v", i, got[i].name, got[i].kind, numbers, w.name, w.kind, w.numbers)
+		}
+	}
+}
+
+func TestGroupPullRequestsByOrganization_noOrganizations(t *testing.T) {
+	got := groupPullRequestsByOrganization("robert", nil, nil)
+
+	kinds := lo.Map(got, func(group pullRequestGroup, _ int) OrganizationSectionKind {
+		return group.kind
+	})
+	if want := []OrganizationSectionKind{OrganizationSectionKindPersonal, OrganizationSectionKindOtherOSS}; !slices.Equal(kinds, want) {
+		t.Errorf("kinds = %v, want %v", kinds, want)
+	}
+}
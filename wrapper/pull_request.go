@@ -2,42 +2,76 @@ package wrapper
 
 import (
 	"fmt"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/filter"
 	"github.com/kmtym1998/gh-wrapped/repository"
-	"github.com/montanaflynn/stats"
 	"github.com/samber/lo"
 )
 
 type WrappedResultPullRequest struct {
-	Login string
+	Login    string
+	Metadata ReportMetadata `compare:"-"`
 	// 当年のすべての PR の数
 	TotalCount int
 	// 当年に作成され、当年にマージされた PR の数
 	MergedCount int
 	// 当年に作成され、当年にマージされなかった、OPEN でない PR の数
 	ClosedCount int
-	// 作成 ~ マージまでが最も短かった PR (上位 3 つ)
-	ShortLivePullRequests []PullRequestDurationItem
-	// 作成 ~ マージまでが最も長かった PR (上位 3 つ)
-	LongLiveRequests []PullRequestDurationItem
-	// 作成 ~ マージまでの平均時間
-	DurationStats PullRequestDuration
+	// マージまでが最も短かった PR (上位 N 件)
+	// マージまでの時間は Metadata.LifetimeFrom の時点から数える
+	ShortLivePullRequests *Ranking[PullRequestDurationItem] `metric:"short_live_pull_requests"`
+	// マージまでが最も長かった PR (上位 N 件)
+	LongLiveRequests *Ranking[PullRequestDurationItem] `metric:"long_live_pull_requests"`
+	// マージまでの時間の統計
+	DurationStats *PullRequestDuration `metric:"duration_stats"`
 	// コメントが最も多くつけられた PR
-	MostCommentedPullRequests []PullRequestRankingItem
+	MostCommentedPullRequests *Ranking[PullRequestRankingItem] `metric:"most_commented_pull_requests"`
 	// コミットが最も多かった PR
-	MostCommittedPullRequests []PullRequestRankingItem
+	MostCommittedPullRequests *Ranking[PullRequestRankingItem] `metric:"most_committed_pull_requests"`
 	// リポジトリごとに PR を出した数
 	SubmissionRanking []PullRequestRankingItem
-	// 一番レビュー回数が多かったユーザー
-	MostReviewedBy string
+	// PR を出したリポジトリの数
+	RepositoryCount int
+	// 一番レビュー回数が多かったユーザー。同じ回数の場合は名前順。--include-bots を指定しなかった場合は bot を除く
+	MostReviewedBy string `metric:"most_reviewed_by"`
+	// PR のサイズ (変更行数) に関する統計
+	Size *PullRequestSizeStats `metric:"size"`
+	// draft で過ごした時間に関する統計
+	Drafts *PullRequestDraftStats `metric:"drafts"`
+	// マージされた PR のレビュー待ち・承認待ち・マージ待ちの時間
+	ReviewPhases *PullRequestReviewPhases `metric:"review_phases"`
+	// マージされた PR を誰がマージしたか、承認なしでマージされたか
+	Merges *PullRequestMergeStats `metric:"merges"`
+	// OPEN・クローズされた PR も含めて推定したマージまでの時間
+	Survival *PullRequestSurvival `metric:"survival"`
+	// 月ごと・週ごとの推移
+	Trends *PullRequestTrends `metric:"trends"`
+	// RegisterPullRequestMetric で追加した、対応するフィールドがない指標の結果
+	Metrics map[string]any
+	// --compare-to で指定した年との比較。指定しなかった場合は nil
+	Comparison *PullRequestComparison
+	// Organization ごとの内訳 (個人リポジトリ・その他 OSS を含む)
+	Organizations []OrganizationSection
+}
+
+type ReportMetadata struct {
+	Year int
+	// PR の生存期間を数え始める時点 (created / ready)
+	LifetimeFrom string
+	// フィルタで除外された PR の数
+	Filter filter.Stats
+	// --disable-metric で集計しなかった指標の名前
+	DisabledMetrics []string
 }
 
 type PullRequestDurationItem struct {
 	PullRequest SimplePullRequest
 	Duration    time.Duration
+	// 営業時間だけで数えた Duration。business_hours を設定していない場合は nil
+	BusinessDuration *time.Duration
 }
 
 type PullRequestRankingItem struct {
@@ -52,6 +86,8 @@ type PullRequestDuration struct {
 	Percentile90 time.Duration
 	Percentile99 time.Duration
 	Max          time.Duration
+	// 営業時間だけで数えた統計値。business_hours を設定していない場合は nil
+	BusinessHours *PullRequestDuration
 }
 
 type SimplePullRequest struct {
@@ -63,244 +99,496 @@ type SimplePullRequest struct {
 }
 
 func WrapPullRequest(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultPullRequest, error) {
+	metrics, err := EnabledPullRequestMetrics(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 有効な指標が使わないレビューやタイムラインのイベントは取得しない
+	source, err := fetchPullRequestSource(repo, cfg, pullRequestFieldsFor(metrics))
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapPullRequest(repo, source, cfg)
+}
+
+// source の PR を集計する。Organization の一覧と --compare-to の年の PR は repo から取得する
+func wrapPullRequest(repo repository.GitHubRepository, source *pullRequestSource, cfg *config.Config) (*WrappedResultPullRequest, error) {
+	metrics, err := EnabledPullRequestMetrics(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	organizations, err := repo.ListOrganizations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	login := source.login
+	now := time.Now()
+	result := wrapPullRequests(newPullRequestDataset(login, source.pullRequests, source.pullRequestsWithBots, cfg, now), metrics)
+	result.Metadata.Filter = source.filterStats
+	if cfg.CompareTo != 0 {
+		previousCfg := cfg.ForYear(cfg.CompareTo)
+		previous, err := fetchPullRequestSource(repo, previousCfg, pullRequestFieldsFor(metrics))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests of %d: %w", cfg.CompareTo, err)
+		}
+
+		result.Comparison = comparePullRequests(
+			wrapPullRequests(newPullRequestDataset(login, previous.pullRequests, previous.pullRequestsWithBots, previousCfg, now), metrics),
+			result,
+			cfg.CompareTo,
+		)
+	}
+	result.Organizations = lo.Map(
+		groupPullRequestsByOrganization(login, organizations, source.pullRequests),
+		func(group pullRequestGroup, _ int) OrganizationSection {
+			return OrganizationSection{
+				Name:   group.name,
+				Kind:   group.kind,
+				Result: wrapPullRequests(newPullRequestDataset(login, group.pullRequests, source.pullRequestsWithBots, cfg, now), metrics),
+			}
+		},
+	)
+
+	return result, nil
+}
+
+// ユーザーと当年に作成した PR。PR を使うセクションを続けて集計するときは 1 回だけ取得して使い回す
+type pullRequestSource struct {
+	login string
+	// アカウントを作成した日時
+	joinedAt time.Time
+	// 当年に作成したすべての PR
+	all []*repository.PullRequest
+	// 設定のフィルタを適用した PR。--include-bots を指定しなかった場合は bot のレビュー・レビューコメントを取り除いている
+	pullRequests []*repository.PullRequest
+	// 設定のフィルタを適用しただけで、bot の活動を取り除いていない PR
+	pullRequestsWithBots []*repository.PullRequest
+	filterStats          filter.Stats
+}
+
+// fields に含まれない PR の項目は取得しない
+func fetchPullRequestSource(repo repository.GitHubRepository, cfg *config.Config, fields repository.PullRequestFields) (*pullRequestSource, error) {
 	user, err := repo.GetMe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	pullRequests, err := repo.ListPullRequests(
-		lo.Must(time.Parse(time.RFC3339, cfg.YearString()+"-01-01T00:00:00Z")),
-		lo.Must(time.Parse(time.RFC3339, cfg.YearString()+"-12-31T23:59:59Z")),
-	)
+	all, err := repo.ListPullRequests(cfg.From(), cfg.To(), fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pull requests: %w", err)
 	}
 
-	result := WrappedResultPullRequest{
-		Login:      user.Login,
-		TotalCount: len(pullRequests),
-		MergedCount: countPullRequestsMergedThisYear(
-			pullRequests,
-			cfg.Year(),
-		),
-		ClosedCount: lo.CountBy(pullRequests, func(pr *repository.PullRequest) bool {
-			if pr.CreatedAt.Year() != cfg.Year() {
-				return false
-			}
+	pullRequestsWithBots, filterStats, err := filterPullRequests(all, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-			if pr.ClosedAt.Valid && pr.ClosedAt.Time.Year() != cfg.Year() {
-				return false
-			}
+	return &pullRequestSource{
+		login:                user.Login,
+		joinedAt:             user.CreatedAt,
+		all:                  all,
+		pullRequests:         withoutExcludedBotActivity(pullRequestsWithBots, cfg),
+		pullRequestsWithBots: pullRequestsWithBots,
+		filterStats:          filterStats,
+	}, nil
+}
 
-			return pr.State == repository.PullRequestStateClosed
+// cfg の年に作成した PR を取得し、設定のフィルタを適用して返す
+// --include-bots を指定しなかった場合は bot のレビュー・レビューコメントを取り除く。fields に含まれない PR の項目は取得しない
+func listFilteredPullRequests(repo repository.GitHubRepository, cfg *config.Config, fields repository.PullRequestFields) ([]*repository.PullRequest, error) {
+	pullRequests, err := repo.ListPullRequests(cfg.From(), cfg.To(), fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	pullRequests, _, err = filterPullRequests(pullRequests, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutExcludedBotActivity(pullRequests, cfg), nil
+}
+
+func filterPullRequests(pullRequests []*repository.PullRequest, cfg *config.Config) ([]*repository.PullRequest, filter.Stats, error) {
+	prFilter, err := filter.New(cfg.Filter)
+	if err != nil {
+		return nil, filter.Stats{}, fmt.Errorf("failed to create pull request filter: %w", err)
+	}
+	pullRequests, filterStats := prFilter.Apply(pullRequests)
+
+	return pullRequests, filterStats, nil
+}
+
+// --include-bots を指定しなかった場合は bot のレビュー・レビューコメントを取り除いた PR を返す
+func withoutExcludedBotActivity(pullRequests []*repository.PullRequest, cfg *config.Config) []*repository.PullRequest {
+	if cfg.IncludeBots {
+		return pullRequests
+	}
+
+	return withoutBotActivity(pullRequests, newBotMatcher(cfg))
+}
+
+// pullRequestsWithBots は bot の活動を取り除く前の PR。nil の場合は pullRequests のレビューに bot のレビューも含まれているとみなす
+func newPullRequestDataset(
+	login string,
+	pullRequests []*repository.PullRequest,
+	pullRequestsWithBots []*repository.PullRequest,
+	cfg *config.Config,
+	now time.Time,
+) *PullRequestDataset {
+	return &PullRequestDataset{
+		Login:        login,
+		PullRequests: pullRequests,
+		MergedPullRequests: lo.Filter(pullRequests, func(pr *repository.PullRequest, _ int) bool {
+			return isPullRequestMerged(pr)
 		}),
-		ShortLivePullRequests: pickTopNPullRequestsDurationItemAsc(
-			pullRequests,
-			3,
-			func(pr1, pr2 *repository.PullRequest) bool {
-				if pr1.State != repository.PullRequestStateMerged {
-					return false
-				}
-
-				if !pr1.MergedAt.Valid {
-					return false
-				}
-
-				if pr2.State != repository.PullRequestStateMerged {
-					return true
-				}
-
-				if !pr2.MergedAt.Valid {
-					return true
-				}
-
-				sub1 := pr1.MergedAt.Time.Sub(pr1.CreatedAt)
-				sub2 := pr2.MergedAt.Time.Sub(pr2.CreatedAt)
-
-				return sub1 < sub2
-			},
-		),
-		LongLiveRequests: pickTopNPullRequestsDurationItemAsc(
-			pullRequests,
-			3,
-			func(pr1, pr2 *repository.PullRequest) bool {
-				if pr1.State != repository.PullRequestStateMerged {
-					return false
-				}
-
-				if !pr1.MergedAt.Valid {
-					return false
-				}
-
-				if pr2.State != repository.PullRequestStateMerged {
-					return true
-				}
-
-				if !pr2.MergedAt.Valid {
-					return true
-				}
-
-				sub1 := pr1.MergedAt.Time.Sub(pr1.CreatedAt)
-				sub2 := pr2.MergedAt.Time.Sub(pr2.CreatedAt)
-
-				return sub1 > sub2
-			},
-		),
-		DurationStats: func() PullRequestDuration {
-			// average
-			sum := lo.SumBy(pullRequests, func(pr *repository.PullRequest) time.Duration {
-				if pr.State != repository.PullRequestStateMerged {
-					return 0
-				}
-
-				if !pr.MergedAt.Valid {
-					return 0
-				}
-
-				return pr.MergedAt.Time.Sub(pr.CreatedAt)
-			})
-			avg := sum / time.Duration(countPullRequestsMergedThisYear(pullRequests, cfg.Year()))
-
-			prLifetimes := lo.FilterMap(
-				pullRequests,
-				func(pr *repository.PullRequest, _ int) (float64, bool) {
-					if pr.State != repository.PullRequestStateMerged {
-						return 0, false
-					}
-
-					if !pr.MergedAt.Valid {
-						return 0, false
-					}
-
-					return float64(pr.MergedAt.Time.Sub(pr.CreatedAt)), true
+		Config: cfg,
+		Now:    now,
+		reviewsWithBots: lo.SliceToMap(pullRequestsWithBots, func(pr *repository.PullRequest) (string, []repository.PullRequestReview) {
+			return pr.ID, pr.Reviews
+		}),
+	}
+}
+
+// 取得済みの PR から件数などの基本的な値を数え、metrics の指標を計算して集計結果を組み立てる
+// 基本的な値と StreamingMetric は PR を 1 回たどるだけで計算する
+func wrapPullRequests(dataset *PullRequestDataset, metrics []Metric) *WrappedResultPullRequest {
+	cfg := dataset.Config
+
+	type namedAccumulator struct {
+		name        string
+		accumulator MetricAccumulator
+	}
+	var accumulators []namedAccumulator
+	var batchMetrics []Metric
+	for _, metric := range metrics {
+		if streaming, ok := metric.(StreamingMetric); ok {
+			accumulators = append(accumulators, namedAccumulator{name: metric.Name(), accumulator: streaming.NewAccumulator(dataset)})
+		} else {
+			batchMetrics = append(batchMetrics, metric)
+		}
+	}
+
+	counts := newPullRequestCounts(cfg)
+	for _, pr := range dataset.PullRequests {
+		counts.add(pr)
+		for _, a := range accumulators {
+			a.accumulator.Add(pr)
+		}
+	}
+
+	result := WrappedResultPullRequest{
+		Login: dataset.Login,
+		Metadata: ReportMetadata{
+			Year:            cfg.Year(),
+			LifetimeFrom:    cfg.LifetimeFrom,
+			DisabledMetrics: cfg.DisabledMetrics,
+		},
+		TotalCount:      counts.total,
+		MergedCount:     counts.merged,
+		ClosedCount:     counts.closed,
+		RepositoryCount: len(counts.repositories),
+	}
+	for _, a := range accumulators {
+		result.setMetric(a.name, a.accumulator.Result())
+	}
+	for _, metric := range batchMetrics {
+		result.setMetric(metric.Name(), metric.Compute(dataset))
+	}
+
+	return &result
+}
+
+// WrappedResultPullRequest の指標以外の基本的な値を PR を 1 件ずつ受け取って数える
+type pullRequestCounts struct {
+	cfg   *config.Config
+	total int
+	// 当年に作成され、当年にマージされた PR の数
+	merged int
+	// 当年に作成され、当年にマージされなかった、OPEN でない PR の数
+	closed       int
+	repositories map[string]bool
+}
+
+func newPullRequestCounts(cfg *config.Config) *pullRequestCounts {
+	return &pullRequestCounts{
+		cfg:          cfg,
+		repositories: map[string]bool{},
+	}
+}
+
+func (c *pullRequestCounts) add(pr *repository.PullRequest) {
+	c.total++
+	if isPullRequestMergedThisYear(pr, c.cfg.Year(), c.cfg.Location()) {
+		c.merged++
+	}
+	if isPullRequestClosedThisYear(pr, c.cfg.Year(), c.cfg.Location()) {
+		c.closed++
+	}
+	c.repositories[pr.RepositoryFullName()] = true
+}
+
+// 自分の PR にレビューをつけたユーザーごとのレビュー数を数え、最も多かったユーザーを返す
+type mostReviewedByAccumulator struct {
+	login     string
+	reviewers map[string]int
+}
+
+func (a *mostReviewedByAccumulator) Add(pr *repository.PullRequest) {
+	for _, review := range pr.Reviews {
+		// 削除されたユーザー (ghost) と自分自身のレビューは数えない
+		if review.SubmittedAt.Valid && review.Author != "" && !strings.EqualFold(review.Author, a.login) {
+			a.reviewers[review.Author]++
+		}
+	}
+}
+
+// レビューされていない場合は空
+func (a *mostReviewedByAccumulator) Result() any {
+	if len(a.reviewers) == 0 {
+		return ""
+	}
+
+	return pickTopNCountRankingItemDesc(a.reviewers, 1).Items[0].Name
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"most_reviewed_by",
+		[]MetricData{MetricDataReviews},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &mostReviewedByAccumulator{login: dataset.Login, reviewers: map[string]int{}}
+		},
+	))
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		config.RankingShortLivePullRequests,
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return newLifetimeRankingAccumulator(
+				dataset.Config,
+				newRankingAsc[*repository.PullRequest, time.Duration](
+					dataset.Config.TopN(config.RankingShortLivePullRequests),
+					lessPullRequestForTie,
+				),
+			)
+		},
+	))
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		config.RankingLongLivePullRequests,
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return newLifetimeRankingAccumulator(
+				dataset.Config,
+				newRankingDesc[*repository.PullRequest, time.Duration](
+					dataset.Config.TopN(config.RankingLongLivePullRequests),
+					lessPullRequestForTie,
+				),
+			)
+		},
+	))
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"duration_stats",
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &durationStatsAccumulator{cfg: dataset.Config}
+		},
+	))
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		config.RankingMostCommentedPullRequests,
+		[]MetricData{MetricDataReviewComments},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return newPullRequestRankingAccumulator(
+				dataset.Config.TopN(config.RankingMostCommentedPullRequests),
+				func(pr *repository.PullRequest) int {
+					return pr.CommentsCount
 				},
 			)
+		},
+	))
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		config.RankingMostCommittedPullRequests,
+		[]MetricData{MetricDataPullRequests},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return newPullRequestRankingAccumulator(
+				dataset.Config.TopN(config.RankingMostCommittedPullRequests),
+				func(pr *repository.PullRequest) int {
+					return pr.CommitsCount
+				},
+			)
+		},
+	))
+}
 
-			return PullRequestDuration{
-				Average: avg,
-				Min:     time.Duration(lo.Min(prLifetimes)),
-				Percentile50: time.Duration(
-					lo.Must(stats.Percentile(prLifetimes, 50)),
-				) * time.Nanosecond,
-				Percentile90: time.Duration(
-					lo.Must(stats.Percentile(prLifetimes, 90)),
-				) * time.Nanosecond,
-				Percentile99: time.Duration(
-					lo.Must(stats.Percentile(prLifetimes, 99)),
-				) * time.Nanosecond,
-				Max: time.Duration(lo.Max(prLifetimes)),
-			}
-		}(),
-		MostCommentedPullRequests: pickTopNPullRequestRankingItemDesc(
-			pullRequests,
-			3,
-			func(pr *repository.PullRequest) int {
-				return pr.CommentsCount
-			},
-		),
-		MostCommittedPullRequests: pickTopNPullRequestRankingItemDesc(
-			pullRequests,
-			3,
-			func(pr *repository.PullRequest) int {
-				return pr.CommitsCount
-			},
-		),
-	}
-
-	return &result, nil
+// マージされた PR をマージまでの時間で並べたランキング
+type lifetimeRankingAccumulator struct {
+	cfg     *config.Config
+	ranking *topN[rankingEntry[*repository.PullRequest, time.Duration]]
 }
 
-// valueFunc で指定した値の降順で並べた上で、上位 n 件を返す
-func pickTopNPullRequestRankingItemDesc(
-	list []*repository.PullRequest,
-	n int,
-	valueFunc func(pr *repository.PullRequest) int,
-) []PullRequestRankingItem {
-	if n < 1 {
-		panic("n must be greater than 0")
-	}
+func newLifetimeRankingAccumulator(
+	cfg *config.Config,
+	ranking *topN[rankingEntry[*repository.PullRequest, time.Duration]],
+) *lifetimeRankingAccumulator {
+	return &lifetimeRankingAccumulator{cfg: cfg, ranking: ranking}
+}
 
-	if valueFunc == nil {
-		panic("compareFunc must not be nil")
+func (a *lifetimeRankingAccumulator) Add(pr *repository.PullRequest) {
+	if !isPullRequestMerged(pr) {
+		return
 	}
 
-	var copiedList []*repository.PullRequest
-	for _, pr := range list {
-		copiedList = append(copiedList, pr)
+	a.ranking.add(rankingEntry[*repository.PullRequest, time.Duration]{
+		item:  pr,
+		value: pullRequestLifetime(pr, a.cfg.LifetimeFrom).duration(),
+	})
+}
+
+func (a *lifetimeRankingAccumulator) Result() any {
+	return toRanking(a.ranking, func(entry rankingEntry[*repository.PullRequest, time.Duration]) PullRequestDurationItem {
+		return newPullRequestDurationItem(entry.item, pullRequestLifetime(entry.item, a.cfg.LifetimeFrom), a.cfg.BusinessHours)
+	})
+}
+
+// PR ごとの件数のランキング。件数が同じ PR は作成日時の古い順に並べる
+type pullRequestRankingAccumulator struct {
+	ranking   *topN[rankingEntry[*repository.PullRequest, int]]
+	valueFunc func(pr *repository.PullRequest) int
+}
+
+func newPullRequestRankingAccumulator(n int, valueFunc func(pr *repository.PullRequest) int) *pullRequestRankingAccumulator {
+	return &pullRequestRankingAccumulator{
+		ranking:   newRankingDesc[*repository.PullRequest, int](n, lessPullRequestForTie),
+		valueFunc: valueFunc,
 	}
+}
+
+func (a *pullRequestRankingAccumulator) Add(pr *repository.PullRequest) {
+	a.ranking.add(rankingEntry[*repository.PullRequest, int]{item: pr, value: a.valueFunc(pr)})
+}
+
+func (a *pullRequestRankingAccumulator) Result() any {
+	return a.pullRequestRanking()
+}
 
-	sort.SliceStable(copiedList, func(i, j int) bool {
-		return valueFunc(copiedList[i]) > valueFunc(copiedList[j])
+func (a *pullRequestRankingAccumulator) pullRequestRanking() Ranking[PullRequestRankingItem] {
+	return toRanking(a.ranking, func(entry rankingEntry[*repository.PullRequest, int]) PullRequestRankingItem {
+		return PullRequestRankingItem{
+			PullRequest: toSimplePullRequest(entry.item),
+			Count:       entry.value,
+		}
 	})
+}
 
-	var result []PullRequestRankingItem
-	for i := 0; i < n; i++ {
-		result = append(result, PullRequestRankingItem{
-			PullRequest: SimplePullRequest{
-				Title:  copiedList[i].Title,
-				Owner:  copiedList[i].RepositoryOwner,
-				Repo:   copiedList[i].RepositoryName,
-				Number: copiedList[i].Number,
-				URL:    copiedList[i].URL,
-			},
-			Count: valueFunc(copiedList[i]),
-		})
+// マージまでの時間の統計。当年にマージされた PR がない場合はゼロ値
+type durationStatsAccumulator struct {
+	cfg *config.Config
+	// 当年に作成され、当年にマージされた PR の数。平均はこの数で割る
+	mergedThisYear int
+	lifetimes      durationSketch
+	// 営業時間だけで数えたマージまでの時間。business_hours を設定していない場合は使わない
+	businessLifetimes durationSketch
+}
+
+func (a *durationStatsAccumulator) Add(pr *repository.PullRequest) {
+	if isPullRequestMergedThisYear(pr, a.cfg.Year(), a.cfg.Location()) {
+		a.mergedThisYear++
+	}
+	if !isPullRequestMerged(pr) {
+		return
+	}
+
+	span := pullRequestLifetime(pr, a.cfg.LifetimeFrom)
+	a.lifetimes.add(span.duration())
+	if a.cfg.BusinessHours != nil {
+		a.businessLifetimes.add(a.cfg.BusinessHours.Duration(span.from, span.to))
+	}
+}
+
+func (a *durationStatsAccumulator) Result() any {
+	if a.mergedThisYear == 0 || a.lifetimes.count == 0 {
+		return PullRequestDuration{}
+	}
+
+	result := a.lifetimes.stats()
+	result.Average = a.lifetimes.sum / time.Duration(a.mergedThisYear)
+	if a.cfg.BusinessHours != nil {
+		result.BusinessHours = lo.ToPtr(a.businessLifetimes.stats())
 	}
 
 	return result
 }
 
-// compareFunc で指定した順に昇順で並べた上で、上位 n 件を返す
-func pickTopNPullRequestsDurationItemAsc(
+// valueFunc で指定した値の降順で並べた上で、上位 n 件を返す
+// 値が同じ PR は作成日時の古い順に並べる
+func pickTopNPullRequestRankingItemDesc(
 	list []*repository.PullRequest,
 	n int,
-	compareFunc func(a, b *repository.PullRequest) bool,
-) []PullRequestDurationItem {
-	if n < 1 {
-		panic("n must be greater than 0")
-	}
+	valueFunc func(pr *repository.PullRequest) int,
+) Ranking[PullRequestRankingItem] {
+	return pickTopNRankingItemDesc(
+		list,
+		n,
+		valueFunc,
+		lessPullRequestForTie,
+		func(pr *repository.PullRequest, count int) PullRequestRankingItem {
+			return PullRequestRankingItem{
+				PullRequest: toSimplePullRequest(pr),
+				Count:       count,
+			}
+		},
+	)
+}
 
-	if compareFunc == nil {
-		panic("compareFunc must not be nil")
+func toSimplePullRequest(pr *repository.PullRequest) SimplePullRequest {
+	return SimplePullRequest{
+		Title:  pr.Title,
+		Owner:  pr.RepositoryOwner,
+		Repo:   pr.RepositoryName,
+		Number: pr.Number,
+		URL:    pr.URL,
 	}
+}
 
-	var copiedList []*repository.PullRequest
-	for _, pr := range list {
-		copiedList = append(copiedList, pr)
+// 同順位の PR を並べる順番。作成日時が古い順、同じなら URL の辞書順
+func lessPullRequestForTie(a, b *repository.PullRequest) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
 	}
 
-	sort.SliceStable(copiedList, func(i, j int) bool {
-		return compareFunc(copiedList[i], copiedList[j])
-	})
+	return a.URL < b.URL
+}
+
+// マージされた PR か。マージ日時が取得できていない PR は含めない
+func isPullRequestMerged(pr *repository.PullRequest) bool {
+	return pr.State == repository.PullRequestStateMerged && pr.MergedAt.Valid
+}
 
-	var result []PullRequestDurationItem
-	for i := 0; i < n; i++ {
-		result = append(result, PullRequestDurationItem{
-			PullRequest: SimplePullRequest{
-				Title:  copiedList[i].Title,
-				Owner:  copiedList[i].RepositoryOwner,
-				Repo:   copiedList[i].RepositoryName,
-				Number: copiedList[i].Number,
-				URL:    copiedList[i].URL,
-			},
-			Duration: copiedList[i].MergedAt.Time.Sub(copiedList[i].CreatedAt),
-		})
+// 該当の年に作成され、該当の年にマージされた PR か
+func isPullRequestMergedThisYear(pr *repository.PullRequest, year int, loc *time.Location) bool {
+	if pr.CreatedAt.In(loc).Year() != year {
+		return false
 	}
 
-	return result
+	if pr.MergedAt.Valid && pr.MergedAt.Time.In(loc).Year() != year {
+		return false
+	}
+
+	return pr.State == repository.PullRequestStateMerged
 }
 
-// 該当の年に作成され、マージされた PR の数を返す
-func countPullRequestsMergedThisYear(pullRequests []*repository.PullRequest, year int) int {
-	return lo.CountBy(pullRequests, func(pr *repository.PullRequest) bool {
-		if pr.CreatedAt.Year() != year {
-			return false
-		}
+// 該当の年に作成され、該当の年にマージされずにクローズされた PR か
+func isPullRequestClosedThisYear(pr *repository.PullRequest, year int, loc *time.Location) bool {
+	if pr.CreatedAt.In(loc).Year() != year {
+		return false
+	}
 
-		if pr.MergedAt.Valid && pr.MergedAt.Time.Year() != year {
-			return false
-		}
+	if pr.ClosedAt.Valid && pr.ClosedAt.Time.In(loc).Year() != year {
+		return false
+	}
 
-		return pr.State == repository.PullRequestStateMerged
-	})
+	return pr.State == repository.PullRequestStateClosed
 }
@@ -0,0 +1,125 @@
+package wrapper
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/volatiletech/null/v8"
+)
+
+const benchmarkPullRequestsCount = 100000
+
+// ベンチマーク用に合成した、cfg の年に作成された n 件の PR。seed が同じなら同じ PR を返す
+// マージまでの時間は対数正規分布にし、一部は draft から始めてレビュー・コメントもつける
+func syntheticPullRequests(n int, cfg *config.Config, seed int64) []*repository.PullRequest {
+	r := rand.New(rand.NewSource(seed))
+	from := cfg.From()
+	yearLength := cfg.To().Sub(from)
+
+	pullRequests := make([]*repository.PullRequest, 0, n)
+	for i := 0; i < n; i++ {
+		createdAt := from.Add(time.Duration(r.Int63n(int64(yearLength))))
+		// 中央値が約 1 日で、数分から数か月まで広がる
+		lifetime := time.Duration(math.Exp(r.NormFloat64()*2) * float64(24*time.Hour))
+		pr := &repository.PullRequest{
+			ID:              fmt.Sprintf("PR_%d", i),
+			Number:          i + 1,
+			Title:           fmt.Sprintf("Synthetic pull request %d", i+1),
+			RepositoryOwner: fmt.Sprintf("owner%d", r.Intn(10)),
+			RepositoryName:  fmt.Sprintf("repo%d", r.Intn(50)),
+			IsDraft:         r.Intn(10) == 0,
+			CreatedAt:       createdAt,
+			UpdatedAt:       createdAt.Add(lifetime),
+			State:           repository.PullRequestStateOpen,
+			CommitsCount:    1 + r.Intn(20),
+			Additions:       r.Intn(1000),
+			Deletions:       r.Intn(500),
+			ChangedFiles:    1 + r.Intn(30),
+			URL:             fmt.Sprintf("https://github.com/synthetic/%d", i+1),
+		}
+		if pr.IsDraft {
+			pr.TimelineEvents = append(pr.TimelineEvents, repository.PullRequestTimelineEvent{
+				Type:      repository.TimelineEventReadyForReview,
+				CreatedAt: createdAt.Add(lifetime / 3),
+			})
+		}
+
+		for j := r.Intn(4); j > 0; j-- {
+			review := repository.PullRequestReview{
+				ID:          fmt.Sprintf("%s_R%d", pr.ID, j),
+				Author:      fmt.Sprintf("reviewer%d", r.Intn(30)),
+				State:       "COMMENTED",
+				SubmittedAt: null.TimeFrom(createdAt.Add(lifetime / time.Duration(j+1))),
+			}
+			if j == 1 {
+				review.State = "APPROVED"
+			}
+			for k := r.Intn(4); k > 0; k-- {
+				review.Comments = append(review.Comments, repository.PullRequestComment{
+					ID:     fmt.Sprintf("%s_C%d", review.ID, k),
+					Author: review.Author,
+				})
+			}
+			pr.Reviews = append(pr.Reviews, review)
+			pr.CommentsCount += len(review.Comments)
+		}
+
+		switch p := r.Float64(); {
+		case p < 0.8:
+			pr.State = repository.PullRequestStateMerged
+			pr.MergedAt = null.TimeFrom(createdAt.Add(lifetime))
+			pr.ClosedAt = pr.MergedAt
+			pr.MergedBy = pr.RepositoryOwner
+		case p < 0.9:
+			pr.State = repository.PullRequestStateClosed
+			pr.ClosedAt = null.TimeFrom(createdAt.Add(lifetime))
+		}
+
+		pullRequests = append(pullRequests, pr)
+	}
+
+	return pullRequests
+}
+
+// OPEN の PR の経過時間を毎回同じにするため、now は集計期間の終わりにする
+func newBenchmarkDataset(b *testing.B) (*PullRequestDataset, []Metric) {
+	b.Helper()
+
+	cfg := (&config.Config{Top: 3, LifetimeFrom: config.LifetimeFromCreated, StaleDays: 30}).ForYear(2023)
+	metrics, err := EnabledPullRequestMetrics(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return newPullRequestDataset("synthetic", syntheticPullRequests(benchmarkPullRequestsCount, cfg, 1), nil, cfg, cfg.To()), metrics
+}
+
+// 有効なすべての指標をまとめて集計する
+func BenchmarkWrapPullRequests(b *testing.B) {
+	dataset, metrics := newBenchmarkDataset(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wrapPullRequests(dataset, metrics)
+	}
+}
+
+// 指標を 1 つずつ計算する
+func BenchmarkPullRequestMetrics(b *testing.B) {
+	dataset, metrics := newBenchmarkDataset(b)
+
+	for _, metric := range metrics {
+		b.Run(metric.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				metric.Compute(dataset)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package wrapper
+
+import (
+	"cmp"
+)
+
+// 上位 N 件のランキング
+type Ranking[T any] struct {
+	Items []T
+	// N 件目と同順位だったが、件数の上限で Items に入らなかった数
+	TiedBeyondCutoff int `compare:"-"`
+}
+
+// valueFunc で指定した値の降順で並べた上で、上位 n 件を toItem で変換して返す
+// 値が同じものは lessForTie の順に並べる
+func pickTopNRankingItemDesc[T any, V cmp.Ordered, I any](
+	list []T,
+	n int,
+	valueFunc func(T) V,
+	lessForTie func(a, b T) bool,
+	toItem func(v T, value V) I,
+) Ranking[I] {
+	if valueFunc == nil {
+		panic("valueFunc must not be nil")
+	}
+
+	ranking := newRankingDesc[T, V](n, lessForTie)
+	for _, v := range list {
+		ranking.add(rankingEntry[T, V]{item: v, value: valueFunc(v)})
+	}
+
+	return toRanking(ranking, func(entry rankingEntry[T, V]) I {
+		return toItem(entry.item, entry.value)
+	})
+}
+
+// 値を計算済みのランキングの要素。並べ替えのたびに値を計算し直さないようにする
+type rankingEntry[T any, V cmp.Ordered] struct {
+	item  T
+	value V
+}
+
+// 値の降順、値が同じものは lessForTie の順に上位 n 件を保持する
+func newRankingDesc[T any, V cmp.Ordered](n int, lessForTie func(a, b T) bool) *topN[rankingEntry[T, V]] {
+	return newTopN(
+		n,
+		func(a, b rankingEntry[T, V]) bool {
+			if a.value != b.value {
+				return a.value > b.value
+			}
+			return lessForTie(a.item, b.item)
+		},
+		func(a, b rankingEntry[T, V]) bool {
+			return a.value == b.value
+		},
+	)
+}
+
+// 値の昇順、値が同じものは lessForTie の順に上位 n 件を保持する
+func newRankingAsc[T any, V cmp.Ordered](n int, lessForTie func(a, b T) bool) *topN[rankingEntry[T, V]] {
+	return newTopN(
+		n,
+		func(a, b rankingEntry[T, V]) bool {
+			if a.value != b.value {
+				return a.value < b.value
+			}
+			return lessForTie(a.item, b.item)
+		},
+		func(a, b rankingEntry[T, V]) bool {
+			return a.value == b.value
+		},
+	)
+}
+
+func toRanking[T, I any](t *topN[T], toItem func(T) I) Ranking[I] {
+	var result Ranking[I]
+	for _, v := range t.sorted() {
+		result.Items = append(result.Items, toItem(v))
+	}
+	result.TiedBeyondCutoff = t.tiedBeyondCutoff
+
+	return result
+}
+
+// リポジトリやユーザーなど、名前ごとの件数のランキングの要素
+type CountRankingItem struct {
+	Name  string
+	Count int
+}
+
+// counts を件数の降順で並べた上で、上位 n 件を返す。件数が同じ場合は名前の辞書順
+func pickTopNCountRankingItemDesc(counts map[string]int, n int) Ranking[CountRankingItem] {
+	ranking := newRankingDesc[string, int](n, func(a, b string) bool {
+		return a < b
+	})
+	for name, count := range counts {
+		ranking.add(rankingEntry[string, int]{item: name, value: count})
+	}
+
+	result := toRanking(ranking, func(entry rankingEntry[string, int]) CountRankingItem {
+		return CountRankingItem{Name: entry.item, Count: entry.value}
+	})
+	// JSON で null ではなく空の配列にする
+	if result.Items == nil {
+		result.Items = []CountRankingItem{}
+	}
+
+	return result
+}
@@ -0,0 +1,100 @@
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPickTopNCountRankingItemDesc(t *testing.T) {
+	counts := map[string]int{"alice": 5, "dave": 3, "bob": 3, "carol": 3, "eve": 1}
+
+	tests := []struct {
+		name                 string
+		counts               map[string]int
+		n                    int
+		want                 []CountRankingItem
+		wantTiedBeyondCutoff int
+	}{
+		{
+			name:   "no tie at the cutoff",
+			counts: counts,
+			n:      1,
+			want:   []CountRankingItem{{Name: "alice", Count: 5}},
+		},
+		{
+			name:                 "ties beyond the cutoff are counted",
+			counts:               counts,
+			n:                    2,
+			want:                 []CountRankingItem{{Name: "alice", Count: 5}, {Name: "bob", Count: 3}},
+			wantTiedBeyondCutoff: 2,
+		},
+		{
+			name:                 "ties are ordered by name",
+			counts:               counts,
+			n:                    3,
+			want:                 []CountRankingItem{{Name: "alice", Count: 5}, {Name: "bob", Count: 3}, {Name: "carol", Count: 3}},
+			wantTiedBeyondCutoff: 1,
+		},
+		{
+			name:   "all ties fit",
+			counts: counts,
+			n:      4,
+			want: []CountRankingItem{
+				{Name: "alice", Count: 5},
+				{Name: "bob", Count: 3},
+				{Name: "carol", Count: 3},
+				{Name: "dave", Count: 3},
+			},
+		},
+		{
+			name:   "fewer items than n",
+			counts: map[string]int{"bob": 1, "alice": 1},
+			n:      3,
+			want:   []CountRankingItem{{Name: "alice", Count: 1}, {Name: "bob", Count: 1}},
+		},
+		{
+			name:   "empty",
+			counts: map[string]int{},
+			n:      3,
+			want:   []CountRankingItem{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickTopNCountRankingItemDesc(tt.counts, tt.n)
+			if !reflect.DeepEqual(got.Items, tt.want) {
+				t.Errorf("Items = %v, want %v", got.Items, tt.want)
+			}
+			if got.TiedBeyondCutoff != tt.wantTiedBeyondCutoff {
+				t.Errorf("TiedBeyondCutoff = %d, want %d", got.TiedBeyondCutoff, tt.wantTiedBeyondCutoff)
+			}
+		})
+	}
+}
+
+func TestPickTopNRankingItemDesc_orderIndependent(t *testing.T) {
+	type item struct {
+		name  string
+		value int
+	}
+	pick := func(list []item) Ranking[string] {
+		return pickTopNRankingItemDesc(
+			list,
+			2,
+			func(v item) int { return v.value },
+			func(a, b item) bool { return a.name < b.name },
+			func(v item, _ int) string { return v.name },
+		)
+	}
+
+	want := Ranking[string]{Items: []string{"a", "b"}, TiedBeyondCutoff: 2}
+	for _, list := range [][]item{
+		{{"a", 2}, {"b", 1}, {"c", 1}, {"d", 1}, {"e", 0}},
+		{{"e", 0}, {"d", 1}, {"c", 1}, {"b", 1}, {"a", 2}},
+		{{"c", 1}, {"e", 0}, {"a", 2}, {"d", 1}, {"b", 1}},
+	} {
+		if got := pick(list); !reflect.DeepEqual(got, want) {
+			t.Errorf("pickTopNRankingItemDesc(%v) = %+v, want %+v", list, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package wrapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+type WrappedResultReviews struct {
+	Login string
+	// 当年につけたレビューの数
+	TotalCount int
+	// レビューした PR の数
+	ReviewedPullRequestCount int
+	ApprovedCount            int
+	ChangesRequestedCount    int
+	CommentedCount           int
+	DismissedCount           int
+	// レビューで書いたコメントの数
+	ReviewCommentsCount int
+	// レビューした回数が多かったリポジトリ
+	MostReviewedRepositories Ranking[CountRankingItem]
+	// レビューした回数が多かった PR の作成者。--include-bots を指定しなかった場合は bot を除く
+	MostReviewedAuthors Ranking[CountRankingItem]
+	// レビューをリクエストされてから最初にレビューするまでの時間
+	// 自分へのリクエストがなかった PR (チームへのリクエストや自発的なレビュー) は含まない
+	TimeToFirstReview PullRequestDuration
+}
+
+func WrapReviews(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultReviews, error) {
+	user, err := repo.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return wrapReviews(user.Login, reviews, cfg), nil
+}
+
+func wrapReviews(login string, reviews []*repository.GivenReview, cfg *config.Config) *WrappedResultReviews {
+	countByState := lo.CountValuesBy(reviews, func(review *repository.GivenReview) string {
+		return review.State
+	})
+
+	bots := newBotMatcher(cfg)
+
+	reviewsByPullRequest := lo.GroupBy(reviews, func(review *repository.GivenReview) string {
+		return review.PullRequest.ID
+	})
+
+	return &WrappedResultReviews{
+		Login:                    login,
+		TotalCount:               len(reviews),
+		ReviewedPullRequestCount: len(reviewsByPullRequest),
+		ApprovedCount:            countByState["APPROVED"],
+		ChangesRequestedCount:    countByState["CHANGES_REQUESTED"],
+		CommentedCount:           countByState["COMMENTED"],
+		DismissedCount:           countByState["DISMISSED"],
+		ReviewCommentsCount: lo.SumBy(reviews, func(review *repository.GivenReview) int {
+			return review.CommentsCount
+		}),
+		MostReviewedRepositories: pickTopNCountRankingItemDesc(
+			lo.CountValuesBy(reviews, func(review *repository.GivenReview) string {
+				return review.PullRequest.RepositoryFullName()
+			}),
+			cfg.TopN(config.RankingMostReviewedRepositories),
+		),
+		MostReviewedAuthors: pickTopNCountRankingItemDesc(
+			lo.CountValuesBy(
+				lo.Filter(reviews, func(review *repository.GivenReview, _ int) bool {
+					// 削除されたユーザー (ghost) の PR と、--include-bots を指定しなかった場合は bot の PR は数えない
+					return review.PullRequest.Author != "" &&
+						(cfg.IncludeBots || !bots.isBot(review.PullRequest.Author, review.PullRequest.AuthorIsBot))
+				}),
+				func(review *repository.GivenReview) string {
+					return review.PullRequest.Author
+				},
+			),
+			cfg.TopN(config.RankingMostReviewedAuthors),
+		),
+		TimeToFirstReview: spanStats(
+			lo.FilterMap(lo.Values(reviewsByPullRequest), func(reviews []*repository.GivenReview, _ int) (timeSpan, bool) {
+				return timeToFirstReview(login, reviews)
+			}),
+			cfg.BusinessHours,
+		),
+	}
+}
+
+// 同じ PR へのレビューのうち最初のものについて、それより前に自分にリクエストされた時点からの期間を返す
+// 自分の PR へのレビューはリクエストされることがないので含めない
+func timeToFirstReview(login string, reviews []*repository.GivenReview) (timeSpan, bool) {
+	if len(reviews) == 0 {
+		return timeSpan{}, false
+	}
+
+	first := lo.MinBy(reviews, func(a, b *repository.GivenReview) bool {
+		return a.SubmittedAt.Before(b.SubmittedAt)
+	})
+	if strings.EqualFold(first.PullRequest.Author, login) {
+		return timeSpan{}, false
+	}
+
+	requests := lo.Filter(first.PullRequest.ReviewRequests, func(request repository.ReviewRequest, _ int) bool {
+		return strings.EqualFold(request.Reviewer, login) && !request.RequestedAt.After(first.SubmittedAt)
+	})
+	if len(requests) == 0 {
+		return timeSpan{}, false
+	}
+
+	// 再リクエストされている場合も、最初にリクエストされた時点から数える
+	requestedAt := lo.MinBy(requests, func(a, b repository.ReviewRequest) bool {
+		return a.RequestedAt.Before(b.RequestedAt)
+	}).RequestedAt
+
+	return timeSpan{from: requestedAt, to: first.SubmittedAt}, true
+}
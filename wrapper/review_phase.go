@@ -0,0 +1,192 @@
+package wrapper
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v8"
+)
+
+// マージされた PR がどこで待たされていたか
+type PullRequestReviewPhases struct {
+	// レビュー待ちになってから最初のレビューまで
+	WaitingForFirstReview PullRequestDuration
+	// 最初のレビューから承認まで
+	FirstReviewToApproval PullRequestDuration
+	// 承認からマージまで
+	ApprovalToMerge PullRequestDuration
+	// 最初のレビューまでが最も長かった PR
+	SlowestFirstReview Ranking[PullRequestDurationItem]
+	// 最初のレビューから承認までが最も長かった PR
+	SlowestApproval Ranking[PullRequestDurationItem]
+	// 承認からマージまでが最も長かった PR
+	SlowestMergeAfterApproval Ranking[PullRequestDurationItem]
+}
+
+// PR ごとの各フェーズの開始・終了日時。該当するイベントがない場合は Valid でない
+type pullRequestReviewTimeline struct {
+	pullRequest *repository.PullRequest
+	// レビュー待ちになった日時
+	// Ready for review になった日時 (draft でなければ作成日時) と、その後最初にレビューをリクエストされた日時の遅い方
+	waitingSince  time.Time
+	firstReviewAt null.Time
+	approvedAt    null.Time
+}
+
+func newPullRequestReviewTimeline(login string, pr *repository.PullRequest) pullRequestReviewTimeline {
+	readyAt := readyForReviewAt(pr)
+	waitingSince := readyAt
+	if event, found := lo.Find(pr.TimelineEvents, func(event repository.PullRequestTimelineEvent) bool {
+		return event.Type == repository.TimelineEventReviewRequested && !event.CreatedAt.Before(readyAt)
+	}); found {
+		waitingSince = event.CreatedAt
+	}
+
+	// 作成者自身のコメントはレビューとみなさない
+	reviews := lo.Filter(pr.Reviews, func(review repository.PullRequestReview, _ int) bool {
+		return review.SubmittedAt.Valid && !strings.EqualFold(review.Author, login)
+	})
+
+	firstSubmittedAt := func(reviews []repository.PullRequestReview) null.Time {
+		if len(reviews) == 0 {
+			return null.Time{}
+		}
+
+		return lo.MinBy(reviews, func(a, b repository.PullRequestReview) bool {
+			return a.SubmittedAt.Time.Before(b.SubmittedAt.Time)
+		}).SubmittedAt
+	}
+
+	return pullRequestReviewTimeline{
+		pullRequest:   pr,
+		waitingSince:  waitingSince,
+		firstReviewAt: firstSubmittedAt(reviews),
+		approvedAt: firstSubmittedAt(lo.Filter(reviews, func(review repository.PullRequestReview, _ int) bool {
+			return review.State == reviewStateApproved
+		})),
+	}
+}
+
+// 各フェーズの期間。開始・終了のどちらかがない場合や、順番が前後している場合は false
+func (t pullRequestReviewTimeline) waitingForFirstReview() (timeSpan, bool) {
+	if !t.firstReviewAt.Valid {
+		return timeSpan{}, false
+	}
+
+	return orderedSpan(t.waitingSince, t.firstReviewAt.Time)
+}
+
+func (t pullRequestReviewTimeline) firstReviewToApproval() (timeSpan, bool) {
+	if !t.firstReviewAt.Valid || !t.approvedAt.Valid {
+		return timeSpan{}, false
+	}
+
+	return orderedSpan(t.firstReviewAt.Time, t.approvedAt.Time)
+}
+
+func (t pullRequestReviewTimeline) approvalToMerge() (timeSpan, bool) {
+	if !t.approvedAt.Valid || !t.pullRequest.MergedAt.Valid {
+		return timeSpan{}, false
+	}
+
+	return orderedSpan(t.approvedAt.Time, t.pullRequest.MergedAt.Time)
+}
+
+func orderedSpan(from, to time.Time) (timeSpan, bool) {
+	if to.Before(from) {
+		return timeSpan{}, false
+	}
+
+	return timeSpan{from: from, to: to}, true
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"review_phases",
+		[]MetricData{MetricDataReviews, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			cfg := dataset.Config
+			return &reviewPhasesAccumulator{
+				login:                 dataset.Login,
+				waitingForFirstReview: newReviewPhaseAccumulator(pullRequestReviewTimeline.waitingForFirstReview, config.RankingSlowestFirstReview, cfg),
+				firstReviewToApproval: newReviewPhaseAccumulator(pullRequestReviewTimeline.firstReviewToApproval, config.RankingSlowestApproval, cfg),
+				approvalToMerge:       newReviewPhaseAccumulator(pullRequestReviewTimeline.approvalToMerge, config.RankingSlowestMergeAfterApproval, cfg),
+			}
+		},
+	))
+}
+
+// マージされた PR の各フェーズの期間を数える
+type reviewPhasesAccumulator struct {
+	login                 string
+	waitingForFirstReview *reviewPhaseAccumulator
+	firstReviewToApproval *reviewPhaseAccumulator
+	approvalToMerge       *reviewPhaseAccumulator
+}
+
+func (a *reviewPhasesAccumulator) Add(pr *repository.PullRequest) {
+	if !isPullRequestMerged(pr) {
+		return
+	}
+
+	timeline := newPullRequestReviewTimeline(a.login, pr)
+	a.waitingForFirstReview.add(timeline)
+	a.firstReviewToApproval.add(timeline)
+	a.approvalToMerge.add(timeline)
+}
+
+func (a *reviewPhasesAccumulator) Result() any {
+	var phases PullRequestReviewPhases
+	phases.WaitingForFirstReview, phases.SlowestFirstReview = a.waitingForFirstReview.result()
+	phases.FirstReviewToApproval, phases.SlowestApproval = a.firstReviewToApproval.result()
+	phases.ApprovalToMerge, phases.SlowestMergeAfterApproval = a.approvalToMerge.result()
+
+	return phases
+}
+
+// 1 つのフェーズの期間の統計値と、期間が最も長かった PR のランキング
+type reviewPhaseAccumulator struct {
+	spanFunc func(t pullRequestReviewTimeline) (timeSpan, bool)
+	calendar *businesshours.Calendar
+	spans    *spanSketch
+	slowest  *topN[rankingEntry[pullRequestReviewTimeline, time.Duration]]
+}
+
+func newReviewPhaseAccumulator(
+	spanFunc func(t pullRequestReviewTimeline) (timeSpan, bool),
+	ranking string,
+	cfg *config.Config,
+) *reviewPhaseAccumulator {
+	return &reviewPhaseAccumulator{
+		spanFunc: spanFunc,
+		calendar: cfg.BusinessHours,
+		spans:    newSpanSketch(cfg.BusinessHours),
+		slowest: newRankingDesc[pullRequestReviewTimeline, time.Duration](
+			cfg.TopN(ranking),
+			func(a, b pullRequestReviewTimeline) bool {
+				return lessPullRequestForTie(a.pullRequest, b.pullRequest)
+			},
+		),
+	}
+}
+
+func (a *reviewPhaseAccumulator) add(timeline pullRequestReviewTimeline) {
+	span, ok := a.spanFunc(timeline)
+	if !ok {
+		return
+	}
+
+	a.spans.add(span)
+	a.slowest.add(rankingEntry[pullRequestReviewTimeline, time.Duration]{item: timeline, value: span.duration()})
+}
+
+func (a *reviewPhaseAccumulator) result() (PullRequestDuration, Ranking[PullRequestDurationItem]) {
+	return a.spans.stats(), toRanking(a.slowest, func(entry rankingEntry[pullRequestReviewTimeline, time.Duration]) PullRequestDurationItem {
+		span, _ := a.spanFunc(entry.item)
+		return newPullRequestDurationItem(entry.item.pullRequest, span, a.calendar)
+	})
+}
@@ -0,0 +1,161 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestPullRequestReviewTimeline_phases(t *testing.T) {
+	createdAt := time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time {
+		return createdAt.Add(time.Duration(hours) * time.Hour)
+	}
+	review := func(author, state string, hours int) repository.PullRequestReview {
+		return repository.PullRequestReview{Author: author, State: state, SubmittedAt: null.TimeFrom(at(hours))}
+	}
+	event := func(eventType string, hours int) repository.PullRequestTimelineEvent {
+		return repository.PullRequestTimelineEvent{Type: eventType, CreatedAt: at(hours)}
+	}
+	pullRequest := func(mergedAtHours int, events []repository.PullRequestTimelineEvent, reviews ...repository.PullRequestReview) *repository.PullRequest {
+		pr := &repository.PullRequest{
+			CreatedAt:      createdAt,
+			State:          repository.PullRequestStateOpen,
+			TimelineEvents: events,
+			Reviews:        reviews,
+		}
+		if mergedAtHours > 0 {
+			pr.State = repository.PullRequestStateMerged
+			pr.MergedAt = null.TimeFrom(at(mergedAtHours))
+		}
+
+		return pr
+	}
+	hours := func(h int) *time.Duration {
+		return lo.ToPtr(time.Duration(h) * time.Hour)
+	}
+
+	// nil はそのフェーズを数えないことを表す
+	tests := []struct {
+		name                      string
+		pr                        *repository.PullRequest
+		wantWaitingForFirstReview *time.Duration
+		wantFirstReviewToApproval *time.Duration
+		wantApprovalToMerge       *time.Duration
+	}{
+		{
+			name: "all phases",
+			pr: pullRequest(10, nil,
+				review("hugo", "COMMENTED", 2),
+				review("hugo", reviewStateApproved, 5),
+			),
+			wantWaitingForFirstReview: hours(2),
+			wantFirstReviewToApproval: hours(3),
+			wantApprovalToMerge:       hours(5),
+		},
+		{
+			name: "no reviews",
+			pr:   pullRequest(10, nil),
+		},
+		{
+			name:                      "not approved",
+			pr:                        pullRequest(10, nil, review("hugo", reviewStateChangesRequested, 2)),
+			wantWaitingForFirstReview: hours(2),
+		},
+		{
+			name:                      "approved in the first review",
+			pr:                        pullRequest(10, nil, review("hugo", reviewStateApproved, 2)),
+			wantWaitingForFirstReview: hours(2),
+			wantFirstReviewToApproval: hours(0),
+			wantApprovalToMerge:       hours(8),
+		},
+		{
+			name: "not merged",
+			pr: pullRequest(0, nil,
+				review("hugo", reviewStateApproved, 2),
+			),
+			wantWaitingForFirstReview: hours(2),
+			wantFirstReviewToApproval: hours(0),
+		},
+		{
+			name: "review requested after creation",
+			pr: pullRequest(10, []repository.PullRequestTimelineEvent{event(repository.TimelineEventReviewRequested, 1)},
+				review("hugo", reviewStateApproved, 4),
+			),
+			wantWaitingForFirstReview: hours(3),
+			wantFirstReviewToApproval: hours(0),
+			wantApprovalToMerge:       hours(6),
+		},
+		{
+			name: "draft reviewed after ready for review",
+			pr: pullRequest(10,
+				[]repository.PullRequestTimelineEvent{
+					// draft のうちのリクエストは数えない
+					event(repository.TimelineEventReviewRequested, 1),
+					event(repository.TimelineEventReadyForReview, 3),
+				},
+				review("hugo", reviewStateApproved, 4),
+			),
+			wantWaitingForFirstReview: hours(1),
+			wantFirstReviewToApproval: hours(0),
+			wantApprovalToMerge:       hours(6),
+		},
+		{
+			name: "draft reviewed before ready for review",
+			pr: pullRequest(10,
+				[]repository.PullRequestTimelineEvent{event(repository.TimelineEventReadyForReview, 3)},
+				review("hugo", "COMMENTED", 1),
+				review("hugo", reviewStateApproved, 4),
+			),
+			wantFirstReviewToApproval: hours(3),
+			wantApprovalToMerge:       hours(6),
+		},
+		{
+			name: "approved after the merge",
+			pr: pullRequest(10, nil,
+				review("hugo", "COMMENTED", 2),
+				review("hugo", reviewStateApproved, 12),
+			),
+			wantWaitingForFirstReview: hours(2),
+			wantFirstReviewToApproval: hours(10),
+		},
+		{
+			name: "reviews by the author and pending reviews are ignored",
+			pr: pullRequest(10, nil,
+				review("robert", "COMMENTED", 1),
+				repository.PullRequestReview{Author: "hugo", State: "PENDING"},
+				review("hugo", reviewStateApproved, 3),
+			),
+			wantWaitingForFirstReview: hours(3),
+			wantFirstReviewToApproval: hours(0),
+			wantApprovalToMerge:       hours(7),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline := newPullRequestReviewTimeline("robert", tt.pr)
+			phases := []struct {
+				name     string
+				spanFunc func(pullRequestReviewTimeline) (timeSpan, bool)
+				want     *time.Duration
+			}{
+				{"waitingForFirstReview", pullRequestReviewTimeline.waitingForFirstReview, tt.wantWaitingForFirstReview},
+				{"firstReviewToApproval", pullRequestReviewTimeline.firstReviewToApproval, tt.wantFirstReviewToApproval},
+				{"approvalToMerge", pullRequestReviewTimeline.approvalToMerge, tt.wantApprovalToMerge},
+			}
+			for _, phase := range phases {
+				span, ok := phase.spanFunc(timeline)
+				switch {
+				case ok != (phase.want != nil):
+					t.Errorf("%s() ok = %v, want %v", phase.name, ok, phase.want != nil)
+				case ok && span.duration() != *phase.want:
+					t.Errorf("%s() = %v, want %v", phase.name, span.duration(), *phase.want)
+				}
+			}
+		})
+	}
+}
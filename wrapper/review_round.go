@@ -0,0 +1,160 @@
+package wrapper
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+const (
+	reviewStateApproved         = "APPROVED"
+	reviewStateChangesRequested = "CHANGES_REQUESTED"
+)
+
+// 作成した PR が変更をリクエストされてから再レビューされるまでの往復
+type WrappedResultReviewRounds struct {
+	Login string
+	// マージ前に作成者以外からレビューされた、マージ済みの PR の数
+	ReviewedMergedCount int
+	// 変更のリクエストを受けずに承認された PR の数
+	FirstPassApprovedCount int
+	// ReviewedMergedCount のうち FirstPassApprovedCount の割合 (0 ~ 1)
+	FirstPassApprovalRate float64
+	// PR あたりの往復の回数の平均
+	AverageCycles float64
+	// 往復の回数ごとの PR の数 (回数の昇順)
+	CycleDistribution []ReviewCycleCount
+	// 往復の回数が最も多かった PR
+	MostCycledPullRequests Ranking[PullRequestRankingItem]
+	// 変更をリクエストされた回数が多かったレビュアー
+	TopChangeRequesters Ranking[CountRankingItem]
+}
+
+type ReviewCycleCount struct {
+	Cycles int
+	Count  int
+}
+
+func WrapReviewRounds(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultReviewRounds, error) {
+	source, err := fetchPullRequestSource(repo, cfg, repository.PullRequestFields{Reviews: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapReviewRounds(source.login, source.pullRequests, cfg), nil
+}
+
+func wrapReviewRounds(login string, pullRequests []*repository.PullRequest, cfg *config.Config) *WrappedResultReviewRounds {
+	reviewedMergedPullRequests := lo.Filter(pullRequests, func(pr *repository.PullRequest, _ int) bool {
+		return pr.State == repository.PullRequestStateMerged &&
+			pr.MergedAt.Valid &&
+			len(reviewsBeforeMerge(login, pr)) > 0
+	})
+
+	cyclesByPullRequest := lo.SliceToMap(reviewedMergedPullRequests, func(pr *repository.PullRequest) (*repository.PullRequest, int) {
+		return pr, countReviewCycles(reviewsBeforeMerge(login, pr))
+	})
+
+	firstPassApprovedCount := lo.CountBy(reviewedMergedPullRequests, func(pr *repository.PullRequest) bool {
+		return isApprovedOnFirstPass(reviewsBeforeMerge(login, pr))
+	})
+
+	result := WrappedResultReviewRounds{
+		Login:                  login,
+		ReviewedMergedCount:    len(reviewedMergedPullRequests),
+		FirstPassApprovedCount: firstPassApprovedCount,
+		MostCycledPullRequests: pickTopNPullRequestRankingItemDesc(
+			reviewedMergedPullRequests,
+			cfg.TopN(config.RankingMostCycledPullRequests),
+			func(pr *repository.PullRequest) int {
+				return cyclesByPullRequest[pr]
+			},
+		),
+		TopChangeRequesters: pickTopNCountRankingItemDesc(
+			countChangeRequestsByReviewer(login, pullRequests),
+			cfg.TopN(config.RankingChangeRequesters),
+		),
+	}
+
+	if len(reviewedMergedPullRequests) == 0 {
+		return &result
+	}
+
+	result.FirstPassApprovalRate = float64(firstPassApprovedCount) / float64(len(reviewedMergedPullRequests))
+	result.AverageCycles = float64(lo.Sum(lo.Values(cyclesByPullRequest))) / float64(len(reviewedMergedPullRequests))
+
+	countByCycles := lo.CountValues(lo.Values(cyclesByPullRequest))
+	for cycles := 0; cycles <= lo.Max(lo.Keys(countByCycles)); cycles++ {
+		result.CycleDistribution = append(result.CycleDistribution, ReviewCycleCount{
+			Cycles: cycles,
+			Count:  countByCycles[cycles],
+		})
+	}
+
+	return &result
+}
+
+// マージまでに作成者以外が提出したレビューを提出日時の昇順で返す
+func reviewsBeforeMerge(login string, pr *repository.PullRequest) []repository.PullRequestReview {
+	reviews := lo.Filter(pr.Reviews, func(review repository.PullRequestReview, _ int) bool {
+		return review.SubmittedAt.Valid &&
+			!strings.EqualFold(review.Author, login) &&
+			(!pr.MergedAt.Valid || !review.SubmittedAt.Time.After(pr.MergedAt.Time))
+	})
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].SubmittedAt.Time.Before(reviews[j].SubmittedAt.Time)
+	})
+
+	return reviews
+}
+
+// 変更をリクエストしたレビュアーが、その後に同じ PR をもう一度レビューした回数を数える
+// 再レビューされないまま別のレビュアーの承認でマージされたものは往復とみなさない
+func countReviewCycles(reviews []repository.PullRequestReview) int {
+	var cycles int
+	for i, review := range reviews {
+		// 削除されたユーザー (ghost) は同じレビュアーかどうか分からないので数えない
+		if review.State != reviewStateChangesRequested || review.Author == "" {
+			continue
+		}
+
+		if lo.ContainsBy(reviews[i+1:], func(later repository.PullRequestReview) bool {
+			return later.Author == review.Author
+		}) {
+			cycles++
+		}
+	}
+
+	return cycles
+}
+
+// 最初の承認より前に変更のリクエストがなければ一発で承認されたとみなす
+func isApprovedOnFirstPass(reviews []repository.PullRequestReview) bool {
+	for _, review := range reviews {
+		switch review.State {
+		case reviewStateApproved:
+			return true
+		case reviewStateChangesRequested:
+			return false
+		}
+	}
+
+	return false
+}
+
+func countChangeRequestsByReviewer(login string, pullRequests []*repository.PullRequest) map[string]int {
+	counts := map[string]int{}
+	for _, pr := range pullRequests {
+		for _, review := range reviewsBeforeMerge(login, pr) {
+			// 削除されたユーザー (ghost) は数えない
+			if review.State == reviewStateChangesRequested && review.Author != "" {
+				counts[review.Author]++
+			}
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,130 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/volatiletech/null/v8"
+)
+
+var reviewRoundCreatedAt = time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+// hours 時間後に提出したレビュー
+func reviewAt(author, state string, hours int) repository.PullRequestReview {
+	return repository.PullRequestReview{
+		Author:      author,
+		State:       state,
+		SubmittedAt: null.TimeFrom(reviewRoundCreatedAt.Add(time.Duration(hours) * time.Hour)),
+	}
+}
+
+func TestCountReviewCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		reviews []repository.PullRequestReview
+		want    int
+	}{
+		{
+			name:    "approved without changes requested",
+			reviews: []repository.PullRequestReview{reviewAt("hugo", reviewStateApproved, 1)},
+			want:    0,
+		},
+		{
+			name: "re-reviewed by the same reviewer",
+			reviews: []repository.PullRequestReview{
+				reviewAt("hugo", reviewStateChangesRequested, 1),
+				reviewAt("hugo", reviewStateApproved, 2),
+			},
+			want: 1,
+		},
+		{
+			name: "approved by another reviewer without a re-review",
+			reviews: []repository.PullRequestReview{
+				reviewAt("hugo", reviewStateChangesRequested, 1),
+				reviewAt("jacob", reviewStateApproved, 2),
+			},
+			want: 0,
+		},
+		{
+			name: "several rounds",
+			reviews: []repository.PullRequestReview{
+				reviewAt("hugo", reviewStateChangesRequested, 1),
+				reviewAt("hugo", reviewStateChangesRequested, 2),
+				reviewAt("jacob", reviewStateChangesRequested, 3),
+				reviewAt("hugo", reviewStateApproved, 4),
+				reviewAt("jacob", reviewStateApproved, 5),
+			},
+			want: 3,
+		},
+		{
+			name: "deleted users are not the same reviewer",
+			reviews: []repository.PullRequestReview{
+				reviewAt("", reviewStateChangesRequested, 1),
+				reviewAt("", reviewStateApproved, 2),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countReviewCycles(tt.reviews); got != tt.want {
+				t.Errorf("countReviewCycles() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapReviewRounds(t *testing.T) {
+	mergedPullRequest := func(number int, reviews ...repository.PullRequestReview) *repository.PullRequest {
+		return &repository.PullRequest{
+			Number:    number,
+			State:     repository.PullRequestStateMerged,
+			CreatedAt: reviewRoundCreatedAt,
+			MergedAt:  null.TimeFrom(reviewRoundCreatedAt.Add(24 * time.Hour)),
+			Reviews:   reviews,
+		}
+	}
+	pullRequests := []*repository.PullRequest{
+		// 一発で承認
+		mergedPullRequest(1, reviewAt("hugo", reviewStateApproved, 1)),
+		// コメントの後に承認されても一発で承認とみなす
+		mergedPullRequest(2, reviewAt("jacob", "COMMENTED", 1), reviewAt("jacob", reviewStateApproved, 2)),
+		// 1 往復
+		mergedPullRequest(3, reviewAt("hugo", reviewStateChangesRequested, 1), reviewAt("hugo", reviewStateApproved, 2)),
+		// 削除されたユーザーの変更のリクエストは往復にもレビュアーのランキングにも数えない
+		mergedPullRequest(4, reviewAt("", reviewStateChangesRequested, 1), reviewAt("jacob", reviewStateApproved, 2)),
+		// 自分のレビューとマージ後のレビューだけの PR は集計しない
+		mergedPullRequest(5, reviewAt("robert", reviewStateApproved, 1), reviewAt("hugo", reviewStateChangesRequested, 48)),
+	}
+	cfg := (&config.Config{Top: 3}).ForYear(2023)
+
+	got := wrapReviewRounds("robert", pullRequests, cfg)
+
+	if got.ReviewedMergedCount != 4 || got.FirstPassApprovedCount != 2 {
+		t.Errorf("ReviewedMergedCount, FirstPassApprovedCount = %d, %d, want 4, 2", got.ReviewedMergedCount, got.FirstPassApprovedCount)
+	}
+	if !almostEqual(got.FirstPassApprovalRate, 0.5) {
+		t.Errorf("FirstPassApprovalRate = %v, want 0.5", got.FirstPassApprovalRate)
+	}
+	if !almostEqual(got.AverageCycles, 0.25) {
+		t.Errorf("AverageCycles = %v, want 0.25", got.AverageCycles)
+	}
+	want := []ReviewCycleCount{{Cycles: 0, Count: 3}, {Cycles: 1, Count: 1}}
+	if len(got.CycleDistribution) != len(want) || got.CycleDistribution[0] != want[0] || got.CycleDistribution[1] != want[1] {
+		t.Errorf("CycleDistribution = %+v, want %+v", got.CycleDistribution, want)
+	}
+	if items := got.TopChangeRequesters.Items; len(items) != 1 || items[0] != (CountRankingItem{Name: "hugo", Count: 1}) {
+		t.Errorf("TopChangeRequesters = %+v, want hugo only", items)
+	}
+}
+
+func TestWrapReviewRounds_noReviews(t *testing.T) {
+	got := wrapReviewRounds("robert", nil, (&config.Config{Top: 3}).ForYear(2023))
+
+	if got.ReviewedMergedCount != 0 || got.FirstPassApprovalRate != 0 || got.AverageCycles != 0 || got.CycleDistribution != nil {
+		t.Errorf("wrapReviewRounds() = %+v, want zero values", got)
+	}
+}
@@ -0,0 +1,89 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestTimeToFirstReview(t *testing.T) {
+	createdAt := time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time {
+		return createdAt.Add(time.Duration(hours) * time.Hour)
+	}
+	reviewedPullRequest := func(author string, isBot bool, requests ...repository.ReviewRequest) repository.ReviewedPullRequest {
+		return repository.ReviewedPullRequest{ID: "PR_1", Author: author, AuthorIsBot: isBot, CreatedAt: createdAt, ReviewRequests: requests}
+	}
+	givenReviews := func(pr repository.ReviewedPullRequest, hours ...int) []*repository.GivenReview {
+		var reviews []*repository.GivenReview
+		for _, h := range hours {
+			reviews = append(reviews, &repository.GivenReview{State: "COMMENTED", SubmittedAt: at(h), PullRequest: pr})
+		}
+		return reviews
+	}
+
+	tests := []struct {
+		name    string
+		reviews []*repository.GivenReview
+		want    time.Duration
+		wantOK  bool
+	}{
+		{
+			name: "no reviews",
+		},
+		{
+			name: "requested to me",
+			// 2 回目のレビューではなく最初のレビューまでを数える
+			reviews: givenReviews(reviewedPullRequest("hugo", false, repository.ReviewRequest{Reviewer: "Robert", RequestedAt: at(1)}), 5, 3),
+			want:    2 * time.Hour,
+			wantOK:  true,
+		},
+		{
+			name: "re-requested after the first review",
+			reviews: givenReviews(reviewedPullRequest("hugo", false,
+				repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(4)},
+				repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(1)},
+				repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(8)},
+			), 6),
+			want:   5 * time.Hour,
+			wantOK: true,
+		},
+		{
+			name:    "self-review",
+			reviews: givenReviews(reviewedPullRequest("robert", false, repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(1)}), 2),
+		},
+		{
+			// draft のうちにレビューし、ready for review になってからリクエストされた
+			name:    "reviewed a draft before the request",
+			reviews: givenReviews(reviewedPullRequest("hugo", false, repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(10)}), 2),
+		},
+		{
+			name:    "requested to a team",
+			reviews: givenReviews(reviewedPullRequest("hugo", false, repository.ReviewRequest{RequestedAt: at(1)}), 2),
+		},
+		{
+			name:    "requested to another reviewer",
+			reviews: givenReviews(reviewedPullRequest("hugo", false, repository.ReviewRequest{Reviewer: "jacob", RequestedAt: at(1)}), 2),
+		},
+		{
+			// bot が作成した PR でも、リクエストされてからレビューするまでの時間は数える
+			name:    "pull request by a bot",
+			reviews: givenReviews(reviewedPullRequest("dependabot[bot]", true, repository.ReviewRequest{Reviewer: "robert", RequestedAt: at(0)}), 3),
+			want:    3 * time.Hour,
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := timeToFirstReview("robert", tt.reviews)
+			if ok != tt.wantOK {
+				t.Fatalf("timeToFirstReview() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.duration() != tt.want {
+				t.Errorf("timeToFirstReview() = %v, want %v", got.duration(), tt.want)
+			}
+		})
+	}
+}
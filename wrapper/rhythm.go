@@ -0,0 +1,166 @@
+package wrapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 曜日・時間帯ごとの活動の傾向
+type WrappedResultRhythm struct {
+	Login string
+	// PR を作成した曜日・時間帯
+	PullRequestsCreated ActivityHistogram
+	// 作成した PR がマージされた曜日・時間帯
+	PullRequestsMerged ActivityHistogram
+	// レビューした曜日・時間帯
+	ReviewsGiven ActivityHistogram
+	// 上の 3 つを合わせたもの
+	AllActivities ActivityHistogram
+	// 朝型 / 夜型 / 日中型。活動がない場合は空
+	Chronotype Chronotype
+	// 土日の活動の割合 (0 ~ 1)
+	WeekendShare float64
+	// 最も活動が多かった曜日。活動がない場合は空
+	MostProductiveWeekday string
+}
+
+// 曜日 × 時間 (0 ~ 23 時) ごとの件数
+type ActivityHistogram struct {
+	// 日曜始まりの曜日ごとの、時間ごとの件数
+	Counts [7][24]int
+	// Counts の 0 ~ 4 の色の濃さ。0 は活動なし
+	Levels [7][24]int
+}
+
+type Chronotype string
+
+const (
+	ChronotypeEarlyBird Chronotype = "early bird"
+	ChronotypeNightOwl  Chronotype = "night owl"
+	ChronotypeDaytime   Chronotype = "daytime"
+)
+
+// 朝型・夜型とみなす時間帯と、その時間帯の活動が全体に占める割合の下限
+const (
+	earlyBirdFromHour = 5
+	earlyBirdToHour   = 9
+	nightOwlFromHour  = 21
+	nightOwlToHour    = 4
+	chronotypeMinRate = 0.15
+)
+
+func WrapRhythm(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultRhythm, error) {
+	// 作成・マージした日時しか使わない
+	source, err := fetchPullRequestSource(repo, cfg, repository.PullRequestFields{})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return wrapRhythm(source.login, source.pullRequests, reviews, cfg), nil
+}
+
+func wrapRhythm(
+	login string,
+	pullRequests []*repository.PullRequest,
+	reviews []*repository.GivenReview,
+	cfg *config.Config,
+) *WrappedResultRhythm {
+	created := lo.Map(pullRequests, func(pr *repository.PullRequest, _ int) time.Time {
+		return pr.CreatedAt
+	})
+	// 翌年にマージされたものは数えない
+	merged := lo.FilterMap(pullRequests, func(pr *repository.PullRequest, _ int) (time.Time, bool) {
+		return pr.MergedAt.Time, pr.MergedAt.Valid && !pr.MergedAt.Time.Before(cfg.From()) && pr.MergedAt.Time.Before(cfg.To())
+	})
+	reviewed := lo.Map(reviews, func(review *repository.GivenReview, _ int) time.Time {
+		return review.SubmittedAt
+	})
+	all := append(append(append([]time.Time{}, created...), merged...), reviewed...)
+
+	histogram := newActivityHistogram(all, cfg.Location())
+
+	result := WrappedResultRhythm{
+		Login:               login,
+		PullRequestsCreated: newActivityHistogram(created, cfg.Location()),
+		PullRequestsMerged:  newActivityHistogram(merged, cfg.Location()),
+		ReviewsGiven:        newActivityHistogram(reviewed, cfg.Location()),
+		AllActivities:       histogram,
+	}
+	if len(all) == 0 {
+		return &result
+	}
+
+	result.Chronotype = histogram.chronotype()
+	result.WeekendShare = float64(histogram.weekdayTotal(time.Saturday)+histogram.weekdayTotal(time.Sunday)) / float64(len(all))
+	// 同じ件数の曜日がある場合は日曜に近い方にする
+	result.MostProductiveWeekday = time.Weekday(lo.MaxBy(lo.Range(7), func(a, b int) bool {
+		return histogram.weekdayTotal(time.Weekday(a)) > histogram.weekdayTotal(time.Weekday(b))
+	})).String()
+
+	return &result
+}
+
+func newActivityHistogram(times []time.Time, loc *time.Location) ActivityHistogram {
+	var histogram ActivityHistogram
+	for _, t := range times {
+		t = t.In(loc)
+		histogram.Counts[t.Weekday()][t.Hour()]++
+	}
+
+	level := newQuartileLevel(lo.Flatten(lo.Map(histogram.Counts[:], func(hours [24]int, _ int) []int {
+		return hours[:]
+	})))
+	for weekday, hours := range histogram.Counts {
+		for hour, count := range hours {
+			histogram.Levels[weekday][hour] = level(count)
+		}
+	}
+
+	return histogram
+}
+
+func (h ActivityHistogram) weekdayTotal(weekday time.Weekday) int {
+	return lo.Sum(h.Counts[weekday][:])
+}
+
+// from 時から to 時の前までの件数。from > to の場合は日付をまたぐ
+func (h ActivityHistogram) hoursTotal(from, to int) int {
+	var total int
+	for _, hours := range h.Counts {
+		for hour := from; hour != to; hour = (hour + 1) % 24 {
+			total += hours[hour]
+		}
+	}
+
+	return total
+}
+
+// 早朝・深夜の活動が全体の chronotypeMinRate 以上あれば、多い方の型とみなす
+func (h ActivityHistogram) chronotype() Chronotype {
+	total := lo.SumBy(lo.Range(7), func(weekday int) int {
+		return h.weekdayTotal(time.Weekday(weekday))
+	})
+	if total == 0 {
+		return ""
+	}
+
+	early := h.hoursTotal(earlyBirdFromHour, earlyBirdToHour)
+	night := h.hoursTotal(nightOwlFromHour, nightOwlToHour)
+	switch {
+	case night > early && float64(night) >= chronotypeMinRate*float64(total):
+		return ChronotypeNightOwl
+	case early >= night && early > 0 && float64(early) >= chronotypeMinRate*float64(total):
+		return ChronotypeEarlyBird
+	default:
+		return ChronotypeDaytime
+	}
+}
@@ -0,0 +1,140 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityHistogram_chronotype(t *testing.T) {
+	// 水曜日
+	day := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	jst := time.FixedZone("JST", 9*60*60)
+	// hour 時の活動を count 件ずつ並べる
+	activities := func(countsByHour map[int]int) []time.Time {
+		var times []time.Time
+		for hour, count := range countsByHour {
+			for i := 0; i < count; i++ {
+				times = append(times, day.Add(time.Duration(hour)*time.Hour+time.Duration(i)*time.Minute))
+			}
+		}
+		return times
+	}
+
+	tests := []struct {
+		name  string
+		times []time.Time
+		loc   *time.Location
+		want  Chronotype
+	}{
+		{
+			name: "no activities",
+			loc:  time.UTC,
+			want: "",
+		},
+		{
+			name:  "only daytime",
+			times: activities(map[int]int{12: 3, 15: 3}),
+			loc:   time.UTC,
+			want:  ChronotypeDaytime,
+		},
+		{
+			name:  "early morning starts at 5",
+			times: activities(map[int]int{5: 1, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeEarlyBird,
+		},
+		{
+			name:  "early morning ends before 9",
+			times: activities(map[int]int{8: 1, 9: 5, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeDaytime,
+		},
+		{
+			name:  "night starts at 21",
+			times: activities(map[int]int{21: 1, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeNightOwl,
+		},
+		{
+			name:  "20 is not night",
+			times: activities(map[int]int{20: 1, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeDaytime,
+		},
+		{
+			name:  "night continues past midnight until before 4",
+			times: activities(map[int]int{0: 1, 3: 1, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeNightOwl,
+		},
+		{
+			// 4 時台は朝型・夜型のどちらにも含めない
+			name:  "4 is neither",
+			times: activities(map[int]int{4: 3, 12: 3}),
+			loc:   time.UTC,
+			want:  ChronotypeDaytime,
+		},
+		{
+			name:  "exactly the minimum rate",
+			times: activities(map[int]int{7: 3, 12: 17}),
+			loc:   time.UTC,
+			want:  ChronotypeEarlyBird,
+		},
+		{
+			name:  "below the minimum rate",
+			times: activities(map[int]int{7: 2, 12: 12}),
+			loc:   time.UTC,
+			want:  ChronotypeDaytime,
+		},
+		{
+			name:  "more night than early morning",
+			times: activities(map[int]int{7: 2, 22: 3, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeNightOwl,
+		},
+		{
+			name:  "tie goes to early bird",
+			times: activities(map[int]int{7: 2, 22: 2, 12: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeEarlyBird,
+		},
+		{
+			// UTC の 22 時・3 時は JST の 7 時・12 時
+			name:  "hours are counted in the configured time zone",
+			times: activities(map[int]int{22: 1, 3: 5}),
+			loc:   jst,
+			want:  ChronotypeEarlyBird,
+		},
+		{
+			name:  "same activities in UTC",
+			times: activities(map[int]int{22: 1, 3: 5}),
+			loc:   time.UTC,
+			want:  ChronotypeNightOwl,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newActivityHistogram(tt.times, tt.loc).chronotype(); got != tt.want {
+				t.Errorf("chronotype() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewActivityHistogram_timeZone(t *testing.T) {
+	// 日曜日の 20 時 (UTC) は、JST では月曜日の 5 時
+	sundayNight := time.Date(2023, time.March, 5, 20, 0, 0, 0, time.UTC)
+
+	utc := newActivityHistogram([]time.Time{sundayNight}, time.UTC)
+	if utc.Counts[time.Sunday][20] != 1 {
+		t.Errorf("Counts[Sunday][20] in UTC = %d, want 1", utc.Counts[time.Sunday][20])
+	}
+
+	jst := newActivityHistogram([]time.Time{sundayNight}, time.FixedZone("JST", 9*60*60))
+	if jst.Counts[time.Monday][5] != 1 {
+		t.Errorf("Counts[Monday][5] in JST = %d, want 1", jst.Counts[time.Monday][5])
+	}
+	if jst.Levels[time.Monday][5] == 0 {
+		t.Errorf("Levels[Monday][5] in JST = 0, want > 0")
+	}
+}
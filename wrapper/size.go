@@ -0,0 +1,184 @@
+package wrapper
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/montanaflynn/stats"
+	"github.com/samber/lo"
+)
+
+type PullRequestSizeStats struct {
+	// 追加した行数の合計
+	TotalAdditions int
+	// 削除した行数の合計
+	TotalDeletions int
+	// 変更したファイル数の合計
+	TotalChangedFiles int
+	// サイズごとの PR の数とマージまでの時間の中央値 (XS → XL の順)
+	Distribution []PullRequestSizeBucket
+	// 変更行数が最も多かった PR
+	LargestPullRequests Ranking[PullRequestRankingItem]
+	// マージされた PR の変更行数とマージまでの時間のスピアマンの順位相関係数 (-1 ~ 1)
+	// 正なら大きい PR ほどマージまでに時間がかかっている。マージされた PR が 2 件未満の場合は 0
+	SizeLifetimeCorrelation float64
+}
+
+type PullRequestSizeBucket struct {
+	Size PullRequestSize
+	// 変更行数 (追加 + 削除) の範囲。MaxLines が 0 の場合は上限なし
+	MinLines int
+	MaxLines int
+	Count    int
+	// このサイズのマージされた PR の、マージまでの時間の中央値
+	MedianLifetime time.Duration
+	// 営業時間だけで数えた MedianLifetime。business_hours を設定していない場合は nil
+	MedianBusinessLifetime *time.Duration
+}
+
+type PullRequestSize string
+
+const (
+	PullRequestSizeXS PullRequestSize = "XS"
+	PullRequestSizeS  PullRequestSize = "S"
+	PullRequestSizeM  PullRequestSize = "M"
+	PullRequestSizeL  PullRequestSize = "L"
+	PullRequestSizeXL PullRequestSize = "XL"
+)
+
+// 変更行数がそれぞれ MaxLines 未満のものをそのサイズとする
+var pullRequestSizeBuckets = []PullRequestSizeBucket{
+	{Size: PullRequestSizeXS, MinLines: 0, MaxLines: 10},
+	{Size: PullRequestSizeS, MinLines: 10, MaxLines: 50},
+	{Size: PullRequestSizeM, MinLines: 50, MaxLines: 250},
+	{Size: PullRequestSizeL, MinLines: 250, MaxLines: 1000},
+	{Size: PullRequestSizeXL, MinLines: 1000, MaxLines: 0},
+}
+
+func pullRequestSizeOf(pr *repository.PullRequest) PullRequestSize {
+	for _, bucket := range pullRequestSizeBuckets {
+		if bucket.MaxLines == 0 || pr.ChangedLines() < bucket.MaxLines {
+			return bucket.Size
+		}
+	}
+
+	return PullRequestSizeXL
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"size",
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			cfg := dataset.Config
+			return &sizeStatsAccumulator{
+				cfg:             cfg,
+				countBySize:     map[PullRequestSize]int{},
+				lifetimesBySize: map[PullRequestSize]*spanSketch{},
+				largest:         newPullRequestRankingAccumulator(cfg.TopN(config.RankingLargestPullRequests), (*repository.PullRequest).ChangedLines),
+			}
+		},
+	))
+}
+
+// 順位相関はすべての値の順位が必要なので、マージされた PR の変更行数とマージまでの時間だけは PR の数だけ持つ
+type sizeStatsAccumulator struct {
+	cfg               *config.Config
+	totalAdditions    int
+	totalDeletions    int
+	totalChangedFiles int
+	countBySize       map[PullRequestSize]int
+	// サイズごとのマージされた PR のマージまでの時間
+	lifetimesBySize map[PullRequestSize]*spanSketch
+	largest         *pullRequestRankingAccumulator
+	// マージされた PR の変更行数とマージまでの時間 (同じ添字が同じ PR)
+	mergedChangedLines []float64
+	mergedLifetimes    []float64
+}
+
+func (a *sizeStatsAccumulator) Add(pr *repository.PullRequest) {
+	a.totalAdditions += pr.Additions
+	a.totalDeletions += pr.Deletions
+	a.totalChangedFiles += pr.ChangedFiles
+	size := pullRequestSizeOf(pr)
+	a.countBySize[size]++
+	a.largest.Add(pr)
+
+	if !isPullRequestMerged(pr) {
+		return
+	}
+
+	lifetime := pullRequestLifetime(pr, a.cfg.LifetimeFrom)
+	if a.lifetimesBySize[size] == nil {
+		a.lifetimesBySize[size] = newSpanSketch(a.cfg.BusinessHours)
+	}
+	a.lifetimesBySize[size].add(lifetime)
+	a.mergedChangedLines = append(a.mergedChangedLines, float64(pr.ChangedLines()))
+	a.mergedLifetimes = append(a.mergedLifetimes, float64(lifetime.duration()))
+}
+
+func (a *sizeStatsAccumulator) Result() any {
+	return PullRequestSizeStats{
+		TotalAdditions:    a.totalAdditions,
+		TotalDeletions:    a.totalDeletions,
+		TotalChangedFiles: a.totalChangedFiles,
+		Distribution: lo.Map(pullRequestSizeBuckets, func(bucket PullRequestSizeBucket, _ int) PullRequestSizeBucket {
+			bucket.Count = a.countBySize[bucket.Size]
+			lifetimes := a.lifetimesBySize[bucket.Size]
+			if lifetimes == nil {
+				lifetimes = newSpanSketch(a.cfg.BusinessHours)
+			}
+			lifetimeStats := lifetimes.stats()
+			bucket.MedianLifetime = lifetimeStats.Percentile50
+			if lifetimeStats.BusinessHours != nil {
+				bucket.MedianBusinessLifetime = lo.ToPtr(lifetimeStats.BusinessHours.Percentile50)
+			}
+
+			return bucket
+		}),
+		LargestPullRequests:     a.largest.pullRequestRanking(),
+		SizeLifetimeCorrelation: spearmanCorrelation(a.mergedChangedLines, a.mergedLifetimes),
+	}
+}
+
+// 外れ値に引きずられないよう、値そのものではなく順位の相関を取る
+func spearmanCorrelation(xs, ys []float64) float64 {
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return 0
+	}
+
+	correlation, err := stats.Correlation(ranks(xs), ranks(ys))
+	if err != nil || math.IsNaN(correlation) {
+		// どちらかの値がすべて同じ場合は相関が定義できない
+		return 0
+	}
+
+	return correlation
+}
+
+// 昇順の順位を返す。同じ値には平均の順位をつける
+func ranks(values []float64) []float64 {
+	indexes := lo.Range(len(values))
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return values[indexes[i]] < values[indexes[j]]
+	})
+
+	result := make([]float64, len(values))
+	for start := 0; start < len(indexes); {
+		end := start
+		for end+1 < len(indexes) && values[indexes[end+1]] == values[indexes[start]] {
+			end++
+		}
+
+		rank := float64(start+end)/2 + 1
+		for i := start; i <= end; i++ {
+			result[indexes[i]] = rank
+		}
+		start = end + 1
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestRanks(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []float64
+	}{
+		{
+			name: "empty",
+			want: []float64{},
+		},
+		{
+			name:   "distinct values",
+			values: []float64{30, 10, 20},
+			want:   []float64{3, 1, 2},
+		},
+		{
+			name:   "ties share the average rank",
+			values: []float64{5, 1, 5, 3, 5},
+			want:   []float64{4, 1, 4, 2, 4},
+		},
+		{
+			name:   "all the same",
+			values: []float64{2, 2},
+			want:   []float64{1.5, 1.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ranks(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpearmanCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		ys   []float64
+		want float64
+	}{
+		{
+			name: "no values",
+			want: 0,
+		},
+		{
+			name: "single value",
+			xs:   []float64{10},
+			ys:   []float64{3},
+			want: 0,
+		},
+		{
+			name: "different lengths",
+			xs:   []float64{1, 2, 3},
+			ys:   []float64{1, 2},
+			want: 0,
+		},
+		{
+			// 値の大きさではなく順位だけを見る
+			name: "monotonic increase",
+			xs:   []float64{1, 2, 3, 1000},
+			ys:   []float64{10, 20, 30, 31},
+			want: 1,
+		},
+		{
+			name: "monotonic decrease",
+			xs:   []float64{1, 2, 3},
+			ys:   []float64{30, 20, 10},
+			want: -1,
+		},
+		{
+			name: "ties",
+			xs:   []float64{1, 2, 2, 3},
+			ys:   []float64{1, 2, 3, 4},
+			want: 0.9486832980505138,
+		},
+		{
+			name: "zero variance",
+			xs:   []float64{5, 5, 5},
+			ys:   []float64{1, 2, 3},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spearmanCorrelation(tt.xs, tt.ys); !almostEqual(got, tt.want) {
+				t.Errorf("spearmanCorrelation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequestSizeOf(t *testing.T) {
+	tests := []struct {
+		additions int
+		deletions int
+		want      PullRequestSize
+	}{
+		{additions: 0, deletions: 0, want: PullRequestSizeXS},
+		{additions: 5, deletions: 4, want: PullRequestSizeXS},
+		{additions: 5, deletions: 5, want: PullRequestSizeS},
+		{additions: 49, deletions: 0, want: PullRequestSizeS},
+		{additions: 0, deletions: 50, want: PullRequestSizeM},
+		{additions: 249, deletions: 0, want: PullRequestSizeM},
+		{additions: 250, deletions: 0, want: PullRequestSizeL},
+		{additions: 500, deletions: 499, want: PullRequestSizeL},
+		{additions: 500, deletions: 500, want: PullRequestSizeXL},
+		{additions: 100000, deletions: 0, want: PullRequestSizeXL},
+	}
+	for _, tt := range tests {
+		pr := &repository.PullRequest{Additions: tt.additions, Deletions: tt.deletions}
+		if got := pullRequestSizeOf(pr); got != tt.want {
+			t.Errorf("pullRequestSizeOf(+%d -%d) = %v, want %v", tt.additions, tt.deletions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package wrapper
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// この件数までは値をそのまま持ち、正確なパーセンタイルを返す
+	durationSketchExactLimit = 10000
+	// 件数が durationSketchExactLimit を超えた後のパーセンタイルの相対誤差
+	durationSketchRelativeAccuracy = 0.01
+)
+
+var durationSketchGamma = (1 + durationSketchRelativeAccuracy) / (1 - durationSketchRelativeAccuracy)
+
+// 経過時間を 1 件ずつ受け取って統計値を計算する
+// 件数が少ないうちは値をすべて持つが、durationSketchExactLimit を超えたら対数の幅のバケットごとの件数にまとめる (DDSketch)
+// 平均・最小・最大は常に正確で、パーセンタイルはバケットにまとめた後は相対誤差 durationSketchRelativeAccuracy 以内になる
+type durationSketch struct {
+	count    int
+	sum      time.Duration
+	min, max time.Duration
+	// バケットにまとめる前の値
+	values []time.Duration
+	// γ^(i-1) < d <= γ^i の d を i のバケットで数える。0 以下の値は zeroCount で数える
+	buckets   map[int]int
+	zeroCount int
+}
+
+func (s *durationSketch) add(d time.Duration) {
+	if s.count == 0 || d < s.min {
+		s.min = d
+	}
+	if s.count == 0 || d > s.max {
+		s.max = d
+	}
+	s.count++
+	s.sum += d
+
+	if s.buckets == nil {
+		s.values = append(s.values, d)
+		if len(s.values) <= durationSketchExactLimit {
+			return
+		}
+
+		s.buckets = map[int]int{}
+		for _, v := range s.values {
+			s.addToBucket(v)
+		}
+		s.values = nil
+		return
+	}
+
+	s.addToBucket(d)
+}
+
+func (s *durationSketch) addToBucket(d time.Duration) {
+	if d <= 0 {
+		s.zeroCount++
+		return
+	}
+
+	s.buckets[int(math.Ceil(math.Log(float64(d))/math.Log(durationSketchGamma)))]++
+}
+
+// 受け取った値の統計値。1 件も受け取っていない場合はゼロ値
+func (s *durationSketch) stats() PullRequestDuration {
+	if s.count == 0 {
+		return PullRequestDuration{}
+	}
+
+	var percentile func(percent float64) time.Duration
+	if s.buckets == nil {
+		sorted := append([]time.Duration{}, s.values...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+		percentile = func(percent float64) time.Duration {
+			return percentileOfSorted(sorted, percent)
+		}
+	} else {
+		percentile = s.bucketPercentile
+	}
+
+	return PullRequestDuration{
+		Average:      s.sum / time.Duration(s.count),
+		Min:          s.min,
+		Percentile50: percentile(50),
+		Percentile90: percentile(90),
+		Percentile99: percentile(99),
+		Max:          s.max,
+	}
+}
+
+// 並べ替え済みの sorted のパーセンタイル。stats.Percentile と同じく、順位が整数でない場合は前後の値の平均にする
+func percentileOfSorted(sorted []time.Duration, percent float64) time.Duration {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	index := percent / 100 * float64(len(sorted))
+	if index == float64(int64(index)) {
+		return sorted[int(index)-1]
+	}
+
+	i := int(index)
+	return time.Duration((float64(sorted[i-1]) + float64(sorted[i])) / 2)
+}
+
+// percent のパーセンタイルが入っているバケットの代表値。最小値・最大値の範囲に収める
+func (s *durationSketch) bucketPercentile(percent float64) time.Duration {
+	rank := int(math.Ceil(percent / 100 * float64(s.count)))
+	if rank <= s.zeroCount {
+		return max(s.min, min(0, s.max))
+	}
+
+	indexes := make([]int, 0, len(s.buckets))
+	for i := range s.buckets {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	seen := s.zeroCount
+	for _, i := range indexes {
+		seen += s.buckets[i]
+		if seen >= rank {
+			value := time.Duration(2 * math.Pow(durationSketchGamma, float64(i)) / (durationSketchGamma + 1))
+			return max(s.min, min(value, s.max))
+		}
+	}
+
+	return s.max
+}
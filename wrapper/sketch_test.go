@@ -0,0 +1,85 @@
+package wrapper
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDurationSketch_exact(t *testing.T) {
+	durations := []time.Duration{4 * time.Hour, time.Hour, 3 * time.Hour, 2 * time.Hour}
+
+	var sketch durationSketch
+	for _, d := range durations {
+		sketch.add(d)
+	}
+
+	// 順位が整数でない場合は前後の値の平均
+	want := PullRequestDuration{
+		Average:      150 * time.Minute,
+		Min:          time.Hour,
+		Percentile50: 2 * time.Hour,
+		Percentile90: 210 * time.Minute,
+		Percentile99: 210 * time.Minute,
+		Max:          4 * time.Hour,
+	}
+	if got := sketch.stats(); got != want {
+		t.Errorf("stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDurationSketch_bucketErrorBound(t *testing.T) {
+	// 数秒から数か月まで広がる値。durationSketchExactLimit を超えるのでバケットにまとめられる
+	r := rand.New(rand.NewSource(1))
+	durations := make([]time.Duration, 5*durationSketchExactLimit)
+	for i := range durations {
+		durations[i] = time.Duration(math.Exp(r.NormFloat64()*3) * float64(time.Hour))
+	}
+	// 0 以下の値は別に数える
+	durations[0], durations[1] = 0, -time.Minute
+
+	var sketch durationSketch
+	for _, d := range durations {
+		sketch.add(d)
+	}
+	if sketch.buckets == nil {
+		t.Fatal("buckets = nil, want the values to be bucketed")
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	got := sketch.stats()
+
+	if got.Min != sorted[0] || got.Max != sorted[len(sorted)-1] {
+		t.Errorf("Min, Max = %v, %v, want %v, %v", got.Min, got.Max, sorted[0], sorted[len(sorted)-1])
+	}
+
+	for _, tt := range []struct {
+		percent float64
+		got     time.Duration
+	}{
+		{50, got.Percentile50},
+		{90, got.Percentile90},
+		{99, got.Percentile99},
+	} {
+		// 順位が ceil(p * n) の値との相対誤差
+		want := sorted[int(math.Ceil(tt.percent/100*float64(len(sorted))))-1]
+		if relativeError := math.Abs(float64(tt.got-want)) / float64(want); relativeError > durationSketchRelativeAccuracy {
+			t.Errorf("Percentile%.0f = %v, want %v within %.0f%% (error %.4f)", tt.percent, tt.got, want, durationSketchRelativeAccuracy*100, relativeError)
+		}
+	}
+}
+
+func TestDurationSketch_bucketPercentileOfZeros(t *testing.T) {
+	var sketch durationSketch
+	for i := 0; i <= durationSketchExactLimit; i++ {
+		sketch.add(0)
+	}
+	sketch.add(time.Hour)
+
+	if got := sketch.stats(); got.Percentile50 != 0 || got.Max != time.Hour {
+		t.Errorf("Percentile50, Max = %v, %v, want 0, 1h", got.Percentile50, got.Max)
+	}
+}
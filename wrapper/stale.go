@@ -0,0 +1,145 @@
+package wrapper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// マージされずにクローズされた理由。GitHub が理由を記録している場合はその値 (not_planned など) を小文字にして使う
+const (
+	closeReasonByAuthor = "closed_by_author"
+	closeReasonByOthers = "closed_by_others"
+	// クローズのイベントが取得できなかった場合
+	closeReasonUnknown = "unknown"
+)
+
+// OPEN のまま放置されている PR と、マージされずにクローズされた PR
+// OPEN の PR は作成した年によらず集計し、マージされずにクローズされた PR は当年に作成したものだけを集計する
+type WrappedResultStalePullRequests struct {
+	Login string
+	// OPEN のまま放置されているとみなす日数
+	StaleDays int
+	// OPEN の PR の数。作成した年は問わない
+	OpenCount int
+	// 作成から StaleDays 日以上経った OPEN の PR の数
+	StaleCount int
+	// 作成から StaleDays 日以上経った OPEN の PR のうち、作成から最も時間が経ったもの
+	OldestOpenPullRequests Ranking[PullRequestDurationItem]
+	// 作成から StaleDays 日以上経った OPEN の PR のうち、最後の更新から最も時間が経ったもの
+	LeastActivePullRequests Ranking[PullRequestDurationItem]
+	// 当年に作成し、マージされずにクローズされた PR (PR の墓場) の数
+	GraveyardCount int
+	// マージされずにクローズされた理由ごとの数
+	CloseReasons []CountRankingItem
+}
+
+func WrapStalePullRequests(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultStalePullRequests, error) {
+	// クローズした理由はタイムラインのイベントから決める
+	source, err := fetchPullRequestSource(repo, cfg, repository.PullRequestFields{TimelineEvents: true})
+	if err != nil {
+		return nil, err
+	}
+
+	openPullRequests, err := listFilteredOpenPullRequests(repo, cfg, openPullRequestFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapStalePullRequests(source.login, source.pullRequests, openPullRequests, cfg, time.Now()), nil
+}
+
+// OPEN の PR は作成日時と更新日時しか使わないので、レビューやタイムラインのイベントは取得しない
+var openPullRequestFields = repository.PullRequestFields{}
+
+// 作成した年によらず OPEN の PR を取得し、設定のフィルタを適用して返す
+func listFilteredOpenPullRequests(
+	repo repository.GitHubRepository,
+	cfg *config.Config,
+	fields repository.PullRequestFields,
+) ([]*repository.PullRequest, error) {
+	openPullRequests, err := repo.ListOpenPullRequests(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open pull requests: %w", err)
+	}
+
+	openPullRequests, _, err = filterPullRequests(openPullRequests, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutExcludedBotActivity(openPullRequests, cfg), nil
+}
+
+// pullRequests は当年に作成した PR、openPullRequests は作成した年によらない OPEN の PR
+// PR の状態は取得した時点のものなので、経過時間は集計対象の年の終わりではなく now までで数える
+func wrapStalePullRequests(
+	login string,
+	pullRequests []*repository.PullRequest,
+	openPullRequests []*repository.PullRequest,
+	cfg *config.Config,
+	now time.Time,
+) *WrappedResultStalePullRequests {
+	age := func(pr *repository.PullRequest) timeSpan {
+		return timeSpan{from: pr.CreatedAt, to: now}
+	}
+	inactivity := func(pr *repository.PullRequest) timeSpan {
+		return timeSpan{from: pr.UpdatedAt, to: now}
+	}
+	stalePullRequests := lo.Filter(openPullRequests, func(pr *repository.PullRequest, _ int) bool {
+		return age(pr).duration() >= time.Duration(cfg.StaleDays)*24*time.Hour
+	})
+	// 作成して間もない PR はランキングに入れない
+	pickLongest := func(spanFunc func(pr *repository.PullRequest) timeSpan, ranking string) Ranking[PullRequestDurationItem] {
+		return pickTopNRankingItemDesc(
+			stalePullRequests,
+			cfg.TopN(ranking),
+			func(pr *repository.PullRequest) time.Duration {
+				return spanFunc(pr).duration()
+			},
+			lessPullRequestForTie,
+			func(pr *repository.PullRequest, _ time.Duration) PullRequestDurationItem {
+				return newPullRequestDurationItem(pr, spanFunc(pr), cfg.BusinessHours)
+			},
+		)
+	}
+
+	graveyard := lo.Filter(pullRequests, func(pr *repository.PullRequest, _ int) bool {
+		return pr.State == repository.PullRequestStateClosed
+	})
+	reasons := lo.CountValuesBy(graveyard, func(pr *repository.PullRequest) string {
+		return closeReason(login, pr)
+	})
+
+	return &WrappedResultStalePullRequests{
+		Login:                   login,
+		StaleDays:               cfg.StaleDays,
+		OpenCount:               len(openPullRequests),
+		StaleCount:              len(stalePullRequests),
+		OldestOpenPullRequests:  pickLongest(age, config.RankingOldestOpenPullRequests),
+		LeastActivePullRequests: pickLongest(inactivity, config.RankingLeastActivePullRequests),
+		GraveyardCount:          len(graveyard),
+		CloseReasons:            pickTopNCountRankingItemDesc(reasons, max(len(reasons), 1)).Items,
+	}
+}
+
+// 最後の ClosedEvent からクローズした理由を決める
+func closeReason(login string, pr *repository.PullRequest) string {
+	event, err := lo.Last(lo.Filter(pr.TimelineEvents, func(event repository.PullRequestTimelineEvent, _ int) bool {
+		return event.Type == repository.TimelineEventClosed
+	}))
+	switch {
+	case err != nil || (event.StateReason == "" && event.Actor == ""):
+		return closeReasonUnknown
+	case event.StateReason != "":
+		return strings.ToLower(event.StateReason)
+	case strings.EqualFold(event.Actor, login):
+		return closeReasonByAuthor
+	default:
+		return closeReasonByOthers
+	}
+}
@@ -0,0 +1,104 @@
+package wrapper
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+func closedEvent(actor, stateReason string) repository.PullRequestTimelineEvent {
+	return repository.PullRequestTimelineEvent{Type: repository.TimelineEventClosed, Actor: actor, StateReason: stateReason}
+}
+
+func TestCloseReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []repository.PullRequestTimelineEvent
+		want   string
+	}{
+		{
+			name: "no events",
+			want: closeReasonUnknown,
+		},
+		{
+			name:   "closed by the author",
+			events: []repository.PullRequestTimelineEvent{closedEvent("Robert", "")},
+			want:   closeReasonByAuthor,
+		},
+		{
+			name:   "closed by others",
+			events: []repository.PullRequestTimelineEvent{closedEvent("hugo", "")},
+			want:   closeReasonByOthers,
+		},
+		{
+			name:   "state reason takes precedence over the actor",
+			events: []repository.PullRequestTimelineEvent{closedEvent("robert", "NOT_PLANNED")},
+			want:   "not_planned",
+		},
+		{
+			name:   "closed by a deleted user without a reason",
+			events: []repository.PullRequestTimelineEvent{closedEvent("", "")},
+			want:   closeReasonUnknown,
+		},
+		{
+			name: "last closed event wins after reopening",
+			events: []repository.PullRequestTimelineEvent{
+				closedEvent("hugo", ""),
+				{Type: repository.TimelineEventReadyForReview},
+				closedEvent("robert", ""),
+				{Type: repository.TimelineEventConvertToDraft},
+			},
+			want: closeReasonByAuthor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closeReason("robert", &repository.PullRequest{TimelineEvents: tt.events}); got != tt.want {
+				t.Errorf("closeReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapStalePullRequests_threshold(t *testing.T) {
+	now := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	openPullRequest := func(number int, ageDays, inactiveDays int) *repository.PullRequest {
+		return &repository.PullRequest{
+			Number:    number,
+			State:     repository.PullRequestStateOpen,
+			CreatedAt: now.AddDate(0, 0, -ageDays),
+			UpdatedAt: now.AddDate(0, 0, -inactiveDays),
+		}
+	}
+	openPullRequests := []*repository.PullRequest{
+		openPullRequest(1, 60, 1),
+		// 作成したばかりで更新もない PR はランキングに入らない
+		openPullRequest(2, 5, 5),
+		// ちょうど StaleDays 日経った PR は放置されているとみなす
+		openPullRequest(3, 30, 20),
+		openPullRequest(4, 29, 29),
+	}
+	cfg := (&config.Config{Top: 3, StaleDays: 30}).ForYear(2023)
+
+	got := wrapStalePullRequests("robert", nil, openPullRequests, cfg, now)
+
+	if got.OpenCount != 4 || got.StaleCount != 2 {
+		t.Errorf("OpenCount, StaleCount = %d, %d, want 4, 2", got.OpenCount, got.StaleCount)
+	}
+	numbers := func(ranking Ranking[PullRequestDurationItem]) []int {
+		return lo.Map(ranking.Items, func(item PullRequestDurationItem, _ int) int {
+			return item.PullRequest.Number
+		})
+	}
+	if got, want := numbers(got.OldestOpenPullRequests), []int{1, 3}; !slices.Equal(got, want) {
+		t.Errorf("OldestOpenPullRequests = %v, want %v", got, want)
+	}
+	if got, want := numbers(got.LeastActivePullRequests), []int{3, 1}; !slices.Equal(got, want) {
+		t.Errorf("LeastActivePullRequests = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package wrapper
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 曲線に残す点の最大数。PR が多い場合は間引く
+const survivalCurveMaxPoints = 100
+
+// 95% 信頼区間の z 値
+const survivalZ = 1.959964
+
+// OPEN の PR も含めた、マージまでの時間の生存時間分析
+// OPEN の PR は打ち切り、マージされずにクローズされた PR はマージと競合するイベントとして扱う (Aalen-Johansen 推定)
+type PullRequestSurvival struct {
+	// 推定に使った PR の数
+	Count         int
+	MergedCount   int
+	ClosedCount   int
+	CensoredCount int
+	// マージされた累積確率が 0.5 に達するまでの時間。達しない場合は nil
+	MedianTimeToMerge *time.Duration
+	// MedianTimeToMerge の 95% 信頼区間。求まらない場合は nil
+	MedianLower *time.Duration
+	MedianUpper *time.Duration
+	// 最後のイベントの時点でマージされた累積確率
+	MergeProbability float64
+	Curve            SurvivalCurve
+	// 営業時間だけで数えた経過時間で推定した結果。business_hours を設定していない場合は nil
+	BusinessHours *PullRequestSurvival
+}
+
+type SurvivalCurve struct {
+	// 経過時間の昇順。先頭は経過時間 0
+	Points []SurvivalPoint
+}
+
+type SurvivalPoint struct {
+	Elapsed time.Duration
+	// まだ OPEN の確率
+	Open float64
+	// マージされた累積確率と 95% 信頼区間
+	Merged      float64
+	MergedLower float64
+	MergedUpper float64
+	// マージされずにクローズされた累積確率
+	Closed float64
+}
+
+type survivalOutcome int
+
+const (
+	survivalCensored survivalOutcome = iota
+	survivalMerged
+	survivalClosed
+)
+
+type survivalObservation struct {
+	elapsed time.Duration
+	// 営業時間だけで数えた elapsed。business_hours を設定していない場合は 0
+	businessElapsed time.Duration
+	outcome         survivalOutcome
+}
+
+// PR が OPEN だった期間と、その後どうなったか。推定に含めない PR は false
+// 生存期間の始まりは cfg.LifetimeFrom に従う。ready の場合、まだ draft の OPEN の PR は始まっていないので含めない
+func newSurvivalObservation(pr *repository.PullRequest, cfg *config.Config, now time.Time) (survivalObservation, bool) {
+	span := pullRequestLifetime(pr, cfg.LifetimeFrom)
+	outcome := survivalMerged
+	switch {
+	case isPullRequestMerged(pr):
+	case pr.State == repository.PullRequestStateClosed && pr.ClosedAt.Valid:
+		span.to, outcome = pr.ClosedAt.Time, survivalClosed
+	case pr.State == repository.PullRequestStateOpen:
+		if cfg.LifetimeFrom == config.LifetimeFromReady && pr.IsDraft {
+			return survivalObservation{}, false
+		}
+		span.to, outcome = now, survivalCensored
+	default:
+		return survivalObservation{}, false
+	}
+
+	observation := survivalObservation{
+		elapsed: max(span.duration(), 0),
+		outcome: outcome,
+	}
+	if cfg.BusinessHours != nil {
+		observation.businessElapsed = max(cfg.BusinessHours.Duration(span.from, span.to), 0)
+	}
+
+	return observation, true
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"survival",
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &survivalAccumulator{cfg: dataset.Config, now: dataset.Now}
+		},
+	))
+}
+
+// 推定には経過時間の順に並べたすべての観測が必要なので、PR ごとの経過時間と結果だけは PR の数だけ持つ
+type survivalAccumulator struct {
+	cfg          *config.Config
+	now          time.Time
+	observations []survivalObservation
+}
+
+func (a *survivalAccumulator) Add(pr *repository.PullRequest) {
+	if o, ok := newSurvivalObservation(pr, a.cfg, a.now); ok {
+		a.observations = append(a.observations, o)
+	}
+}
+
+func (a *survivalAccumulator) Result() any {
+	result := estimatePullRequestSurvival(a.observations)
+	if a.cfg.BusinessHours != nil {
+		result.BusinessHours = estimatePullRequestSurvival(lo.Map(a.observations, func(o survivalObservation, _ int) survivalObservation {
+			return survivalObservation{elapsed: o.businessElapsed, outcome: o.outcome}
+		}))
+	}
+
+	return result
+}
+
+// observations は並べ替える
+func estimatePullRequestSurvival(observations []survivalObservation) *PullRequestSurvival {
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].elapsed < observations[j].elapsed
+	})
+
+	result := PullRequestSurvival{Count: len(observations)}
+	for _, o := range observations {
+		switch o.outcome {
+		case survivalMerged:
+			result.MergedCount++
+		case survivalClosed:
+			result.ClosedCount++
+		default:
+			result.CensoredCount++
+		}
+	}
+
+	// 分散は delta 法で求める。各時点の項を展開して累積和で持つことで O(n) にしている
+	// Var(F(t_k)) = Σ_j [a_j (F_k - F_j)^2 + b_j - 2 c_j (F_k - F_j)]
+	var (
+		open, merged, closed = 1.0, 0.0, 0.0
+		sumA, sumAF, sumAFF  float64
+		sumB, sumC, sumCF    float64
+		points               = []SurvivalPoint{{Open: 1}}
+	)
+	atRisk := len(observations)
+	for i := 0; i < len(observations); {
+		elapsed := observations[i].elapsed
+		var mergedEvents, closedEvents, censored int
+		for ; i < len(observations) && observations[i].elapsed == elapsed; i++ {
+			switch observations[i].outcome {
+			case survivalMerged:
+				mergedEvents++
+			case survivalClosed:
+				closedEvents++
+			default:
+				censored++
+			}
+		}
+
+		events := mergedEvents + closedEvents
+		if events > 0 {
+			n, d, d1 := float64(atRisk), float64(events), float64(mergedEvents)
+			merged += open * d1 / n
+			closed += open * float64(closedEvents) / n
+
+			// n == d の場合は以降の F が変わらないので a_j の項は 0 になる
+			if atRisk > events {
+				a := d / (n * (n - d))
+				sumA += a
+				sumAF += a * merged
+				sumAFF += a * merged * merged
+			}
+			c := open * d1 / (n * n)
+			sumB += open * open * d1 * (n - d1) / (n * n * n)
+			sumC += c
+			sumCF += c * merged
+
+			open *= 1 - d/n
+
+			variance := merged*merged*sumA - 2*merged*sumAF + sumAFF + sumB - 2*merged*sumC + 2*sumCF
+			margin := survivalZ * math.Sqrt(max(variance, 0))
+			points = append(points, SurvivalPoint{
+				Elapsed:     elapsed,
+				Open:        open,
+				Merged:      merged,
+				MergedLower: max(merged-margin, 0),
+				MergedUpper: min(merged+margin, 1),
+				Closed:      closed,
+			})
+		}
+
+		atRisk -= events + censored
+	}
+
+	result.MergeProbability = merged
+	result.MedianTimeToMerge = firstElapsedReaching(points, func(p SurvivalPoint) float64 { return p.Merged })
+	result.MedianLower = firstElapsedReaching(points, func(p SurvivalPoint) float64 { return p.MergedUpper })
+	result.MedianUpper = firstElapsedReaching(points, func(p SurvivalPoint) float64 { return p.MergedLower })
+	result.Curve = SurvivalCurve{Points: thinSurvivalPoints(points, survivalCurveMaxPoints)}
+
+	return &result
+}
+
+// valueFunc が初めて 0.5 以上になった経過時間。ならない場合は nil
+func firstElapsedReaching(points []SurvivalPoint, valueFunc func(p SurvivalPoint) float64) *time.Duration {
+	for _, p := range points {
+		if valueFunc(p) >= 0.5 {
+			elapsed := p.Elapsed
+			return &elapsed
+		}
+	}
+
+	return nil
+}
+
+// 先頭と末尾を残して n 点まで等間隔に間引く
+func thinSurvivalPoints(points []SurvivalPoint, n int) []SurvivalPoint {
+	if len(points) <= n {
+		return points
+	}
+
+	thinned := make([]SurvivalPoint, 0, n)
+	for i := 0; i < n-1; i++ {
+		thinned = append(thinned, points[i*(len(points)-1)/(n-1)])
+	}
+
+	return append(thinned, points[len(points)-1])
+}
@@ -0,0 +1,279 @@
+package wrapper
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v8"
+)
+
+var survivalCreatedAt = time.Date(2023, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+func newPullRequestSurvival(pullRequests []*repository.PullRequest, cfg *config.Config, now time.Time) *PullRequestSurvival {
+	return estimatePullRequestSurvival(newSurvivalObservations(pullRequests, cfg, now))
+}
+
+func newSurvivalObservations(pullRequests []*repository.PullRequest, cfg *config.Config, now time.Time) []survivalObservation {
+	accumulator := &survivalAccumulator{cfg: cfg, now: now}
+	for _, pr := range pullRequests {
+		accumulator.Add(pr)
+	}
+
+	return accumulator.observations
+}
+
+func mergedPullRequestAfter(hours int) *repository.PullRequest {
+	return &repository.PullRequest{
+		State:     repository.PullRequestStateMerged,
+		CreatedAt: survivalCreatedAt,
+		MergedAt:  null.TimeFrom(survivalCreatedAt.Add(time.Duration(hours) * time.Hour)),
+		ClosedAt:  null.TimeFrom(survivalCreatedAt.Add(time.Duration(hours) * time.Hour)),
+	}
+}
+
+func closedPullRequestAfter(hours int) *repository.PullRequest {
+	return &repository.PullRequest{
+		State:     repository.PullRequestStateClosed,
+		CreatedAt: survivalCreatedAt,
+		ClosedAt:  null.TimeFrom(survivalCreatedAt.Add(time.Duration(hours) * time.Hour)),
+	}
+}
+
+func TestNewPullRequestSurvival(t *testing.T) {
+	// 1h でマージ、2h でクローズ、3h の時点で OPEN (打ち切り)、4h でマージ
+	pullRequests := []*repository.PullRequest{
+		mergedPullRequestAfter(4),
+		{State: repository.PullRequestStateOpen, CreatedAt: survivalCreatedAt},
+		closedPullRequestAfter(2),
+		mergedPullRequestAfter(1),
+	}
+	now := survivalCreatedAt.Add(3 * time.Hour)
+
+	got := newPullRequestSurvival(pullRequests, &config.Config{LifetimeFrom: config.LifetimeFromCreated}, now)
+
+	if got.Count != 4 || got.MergedCount != 2 || got.ClosedCount != 1 || got.CensoredCount != 1 {
+		t.Errorf("counts = %d/%d/%d/%d, want 4/2/1/1", got.Count, got.MergedCount, got.ClosedCount, got.CensoredCount)
+	}
+
+	// いずれの時点でも Var(F) = 3/64 になる
+	margin := survivalZ * math.Sqrt(3.0/64)
+	want := []SurvivalPoint{
+		{Elapsed: 0, Open: 1},
+		{Elapsed: 1 * time.Hour, Open: 0.75, Merged: 0.25, MergedLower: 0, MergedUpper: 0.25 + margin},
+		{Elapsed: 2 * time.Hour, Open: 0.5, Merged: 0.25, MergedLower: 0, MergedUpper: 0.25 + margin, Closed: 0.25},
+		{Elapsed: 4 * time.Hour, Open: 0, Merged: 0.75, MergedLower: 0.75 - margin, MergedUpper: 1, Closed: 0.25},
+	}
+	if len(got.Curve.Points) != len(want) {
+		t.Fatalf("len(Points) = %d, want %d: %+v", len(got.Curve.Points), len(want), got.Curve.Points)
+	}
+	for i, w := range want {
+		p := got.Curve.Points[i]
+		if p.Elapsed != w.Elapsed ||
+			!almostEqual(p.Open, w.Open) ||
+			!almostEqual(p.Merged, w.Merged) ||
+			!almostEqual(p.MergedLower, w.MergedLower) ||
+			!almostEqual(p.MergedUpper, w.MergedUpper) ||
+			!almostEqual(p.Closed, w.Closed) {
+			t.Errorf("Points[%d] = %+v, want %+v", i, p, w)
+		}
+	}
+
+	if !almostEqual(got.MergeProbability, 0.75) {
+		t.Errorf("MergeProbability = %v, want 0.75", got.MergeProbability)
+	}
+	if got.MedianTimeToMerge == nil || *got.MedianTimeToMerge != 4*time.Hour {
+		t.Errorf("MedianTimeToMerge = %v, want 4h", got.MedianTimeToMerge)
+	}
+	if got.MedianLower == nil || *got.MedianLower != 1*time.Hour {
+		t.Errorf("MedianLower = %v, want 1h", got.MedianLower)
+	}
+	if got.MedianUpper != nil {
+		t.Errorf("MedianUpper = %v, want nil", *got.MedianUpper)
+	}
+}
+
+func TestNewPullRequestSurvival_withoutCensoring(t *testing.T) {
+	// 打ち切りがなければマージされた累積確率は経験分布と一致する
+	pullRequests := []*repository.PullRequest{
+		mergedPullRequestAfter(1),
+		closedPullRequestAfter(1),
+		mergedPullRequestAfter(2),
+		mergedPullRequestAfter(3),
+		closedPullRequestAfter(3),
+	}
+
+	got := newPullRequestSurvival(pullRequests, &config.Config{LifetimeFrom: config.LifetimeFromCreated}, survivalCreatedAt)
+
+	for i, want := range []float64{0, 0.2, 0.4, 0.6} {
+		if p := got.Curve.Points[i]; !almostEqual(p.Merged, want) {
+			t.Errorf("Points[%d].Merged = %v, want %v", i, p.Merged, want)
+		}
+	}
+	if last := got.Curve.Points[len(got.Curve.Points)-1]; !almostEqual(last.Open, 0) || !almostEqual(last.Closed, 0.4) {
+		t.Errorf("last point = %+v, want Open 0 and Closed 0.4", last)
+	}
+}
+
+func TestNewPullRequestSurvival_variance(t *testing.T) {
+	// 同時刻のイベントと打ち切りを含むデータで、累積和で求めた分散を定義どおりに計算した値と比べる
+	var pullRequests []*repository.PullRequest
+	for i := 0; i < 60; i++ {
+		hours := 1 + (i*7)%13
+		switch i % 5 {
+		case 0, 1, 2:
+			pullRequests = append(pullRequests, mergedPullRequestAfter(hours))
+		case 3:
+			pullRequests = append(pullRequests, closedPullRequestAfter(hours))
+		default:
+			pullRequests = append(pullRequests, &repository.PullRequest{
+				State:     repository.PullRequestStateOpen,
+				CreatedAt: survivalCreatedAt.Add(-time.Duration(hours) * time.Hour),
+			})
+		}
+	}
+	cfg := &config.Config{LifetimeFrom: config.LifetimeFromCreated}
+
+	got := newPullRequestSurvival(pullRequests, cfg, survivalCreatedAt)
+	want := naiveMergedConfidenceIntervals(newSurvivalObservations(pullRequests, cfg, survivalCreatedAt))
+
+	points := got.Curve.Points[1:]
+	if len(points) != len(want) {
+		t.Fatalf("len(Points) = %d, want %d", len(points), len(want))
+	}
+	for i, w := range want {
+		if p := points[i]; !almostEqual(p.MergedLower, w[0]) || !almostEqual(p.MergedUpper, w[1]) {
+			t.Errorf("Points[%d] interval = [%v, %v], want [%v, %v]", i+1, p.MergedLower, p.MergedUpper, w[0], w[1])
+		}
+	}
+}
+
+// イベントのあった時点ごとのマージされた累積確率の 95% 信頼区間を、delta 法の式どおりに O(n^2) で求める
+// Var(F(t_k)) = Σ_{j≤k} [(F_k - F_j)^2 d_j / (n_j (n_j - d_j)) + S_{j-1}^2 d1_j (n_j - d1_j) / n_j^3 - 2 (F_k - F_j) S_{j-1} d1_j / n_j^2]
+func naiveMergedConfidenceIntervals(observations []survivalObservation) [][2]float64 {
+	type step struct {
+		n, d, d1, openBefore, merged float64
+	}
+
+	var steps []step
+	open, merged := 1.0, 0.0
+	for _, elapsed := range uniqueSortedElapsed(observations) {
+		var atRisk, events, mergedEvents int
+		for _, o := range observations {
+			if o.elapsed < elapsed {
+				continue
+			}
+			atRisk++
+			if o.elapsed == elapsed && o.outcome != survivalCensored {
+				events++
+				if o.outcome == survivalMerged {
+					mergedEvents++
+				}
+			}
+		}
+		if events == 0 {
+			continue
+		}
+
+		n, d, d1 := float64(atRisk), float64(events), float64(mergedEvents)
+		merged += open * d1 / n
+		steps = append(steps, step{n: n, d: d, d1: d1, openBefore: open, merged: merged})
+		open *= 1 - d/n
+	}
+
+	intervals := make([][2]float64, 0, len(steps))
+	for k, sk := range steps {
+		var variance float64
+		for _, sj := range steps[:k+1] {
+			diff := sk.merged - sj.merged
+			if sj.n > sj.d {
+				variance += diff * diff * sj.d / (sj.n * (sj.n - sj.d))
+			}
+			variance += sj.openBefore * sj.openBefore * sj.d1 * (sj.n - sj.d1) / (sj.n * sj.n * sj.n)
+			variance -= 2 * diff * sj.openBefore * sj.d1 / (sj.n * sj.n)
+		}
+
+		margin := survivalZ * math.Sqrt(max(variance, 0))
+		intervals = append(intervals, [2]float64{max(sk.merged-margin, 0), min(sk.merged+margin, 1)})
+	}
+
+	return intervals
+}
+
+func uniqueSortedElapsed(observations []survivalObservation) []time.Duration {
+	elapsed := lo.Uniq(lo.Map(observations, func(o survivalObservation, _ int) time.Duration {
+		return o.elapsed
+	}))
+	slices.Sort(elapsed)
+
+	return elapsed
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestThinSurvivalPoints(t *testing.T) {
+	points := make([]SurvivalPoint, 10)
+	for i := range points {
+		points[i] = SurvivalPoint{Elapsed: time.Duration(i) * time.Hour}
+	}
+
+	got := thinSurvivalPoints(points, 4)
+	want := []time.Duration{0, 3 * time.Hour, 6 * time.Hour, 9 * time.Hour}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Elapsed != want[i] {
+			t.Errorf("got[%d].Elapsed = %v, want %v", i, got[i].Elapsed, want[i])
+		}
+	}
+}
+
+func TestNewPullRequestSurvival_businessHours(t *testing.T) {
+	// 3 月 1 日 (水) 9:00 に作成し、1 時間後・翌日・3 日後 (土曜) にマージ
+	pullRequests := []*repository.PullRequest{
+		mergedPullRequestAfter(1),
+		mergedPullRequestAfter(24),
+		mergedPullRequestAfter(72),
+	}
+	cfg := &config.Config{LifetimeFrom: config.LifetimeFromCreated, BusinessHours: businesshours.Default(time.UTC)}
+
+	got := newSurvivalAccumulatorResult(pullRequests, cfg, survivalCreatedAt)
+
+	if got.MedianTimeToMerge == nil || *got.MedianTimeToMerge != 24*time.Hour {
+		t.Errorf("MedianTimeToMerge = %v, want 24h", got.MedianTimeToMerge)
+	}
+	if got.BusinessHours == nil {
+		t.Fatal("BusinessHours = nil")
+	}
+	if median := got.BusinessHours.MedianTimeToMerge; median == nil || *median != 9*time.Hour {
+		t.Errorf("BusinessHours.MedianTimeToMerge = %v, want 9h", median)
+	}
+	wantElapsed := []time.Duration{0, 1 * time.Hour, 9 * time.Hour, 27 * time.Hour}
+	for i, p := range got.BusinessHours.Curve.Points {
+		if i >= len(wantElapsed) || p.Elapsed != wantElapsed[i] {
+			t.Errorf("BusinessHours.Points[%d].Elapsed = %v, want %v", i, p.Elapsed, wantElapsed)
+		}
+	}
+
+	cfg.BusinessHours = nil
+	if got := newSurvivalAccumulatorResult(pullRequests, cfg, survivalCreatedAt); got.BusinessHours != nil {
+		t.Errorf("BusinessHours = %+v, want nil without business_hours", got.BusinessHours)
+	}
+}
+
+func newSurvivalAccumulatorResult(pullRequests []*repository.PullRequest, cfg *config.Config, now time.Time) *PullRequestSurvival {
+	accumulator := &survivalAccumulator{cfg: cfg, now: now}
+	for _, pr := range pullRequests {
+		accumulator.Add(pr)
+	}
+
+	return accumulator.Result().(*PullRequestSurvival)
+}
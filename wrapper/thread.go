@@ -0,0 +1,179 @@
+package wrapper
+
+import (
+	"strings"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 作成した PR のレビューコメントのスレッド
+type WrappedResultThreads struct {
+	Login string
+	// スレッドの数
+	ThreadCount int
+	// 返信がついたスレッドの数
+	DiscussedThreadCount int
+	// スレッドごとの発言の番の数の平均 (返信がなければ 1)
+	AverageTurns float64
+	// 発言の番が最も多かったスレッドの番の数
+	MaxTurns int
+	// 発言の番が最も多かったスレッド。スレッドがない場合は nil
+	LongestThread *ThreadSummary
+	// 自分が始めたスレッドの数
+	StartedCount int
+	// 他の人が始めたスレッドのうち、自分が返信したものの数
+	AnsweredCount int
+	// 返信の数が最も多かった PR
+	MostDebatedPullRequests Ranking[PullRequestRankingItem]
+}
+
+type ThreadSummary struct {
+	PullRequest SimplePullRequest
+	// スレッドのコメント数
+	Comments int
+	// 発言の番の数
+	Turns int
+	// コメントした人の数
+	Participants int
+}
+
+// 最初のコメントとその返信をまとめた 1 つのスレッド
+// GitHub のレビューコメントは返信への返信がなく、返信の ReplyTo は常にスレッドの最初のコメントになる
+type commentThread struct {
+	pullRequest *repository.PullRequest
+	root        repository.PullRequestComment
+	comments    []repository.PullRequestComment
+}
+
+// 同じ人が続けて書いたコメントを 1 つの番として数えた、発言の番の数
+// 2 人が交互に書いたスレッドほど多くなり、返信がなければ 1
+func (t commentThread) turns() int {
+	var turns int
+	for i, comment := range t.comments {
+		if i == 0 || !strings.EqualFold(comment.Author, t.comments[i-1].Author) {
+			turns++
+		}
+	}
+
+	return turns
+}
+
+func WrapThreads(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResultThreads, error) {
+	source, err := fetchPullRequestSource(repo, cfg, repository.PullRequestFields{Reviews: true, ReviewComments: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapThreads(source.login, source.pullRequests, cfg), nil
+}
+
+func wrapThreads(login string, pullRequests []*repository.PullRequest, cfg *config.Config) *WrappedResultThreads {
+	threads := lo.FlatMap(pullRequests, func(pr *repository.PullRequest, _ int) []commentThread {
+		return newCommentThreads(pr)
+	})
+	isMine := func(comment repository.PullRequestComment) bool {
+		return strings.EqualFold(comment.Author, login)
+	}
+
+	result := WrappedResultThreads{
+		Login:       login,
+		ThreadCount: len(threads),
+		DiscussedThreadCount: lo.CountBy(threads, func(t commentThread) bool {
+			return len(t.comments) > 1
+		}),
+		StartedCount: lo.CountBy(threads, func(t commentThread) bool {
+			return isMine(t.root)
+		}),
+		AnsweredCount: lo.CountBy(threads, func(t commentThread) bool {
+			return !isMine(t.root) && lo.ContainsBy(t.comments[1:], isMine)
+		}),
+		MostDebatedPullRequests: pickTopNPullRequestRankingItemDesc(
+			lo.Filter(pullRequests, func(pr *repository.PullRequest, _ int) bool {
+				return countReplies(pr) > 0
+			}),
+			cfg.TopN(config.RankingMostDebatedPullRequests),
+			countReplies,
+		),
+	}
+	if len(threads) == 0 {
+		return &result
+	}
+
+	result.AverageTurns = float64(lo.SumBy(threads, commentThread.turns)) / float64(len(threads))
+	result.MaxTurns = lo.Max(lo.Map(threads, func(t commentThread, _ int) int {
+		return t.turns()
+	}))
+
+	// 発言の番の数が同じスレッドはコメント数の多いもの、それも同じ場合は先に見つかったもの
+	longest := lo.MaxBy(threads, func(a, b commentThread) bool {
+		if a.turns() != b.turns() {
+			return a.turns() > b.turns()
+		}
+		return len(a.comments) > len(b.comments)
+	})
+	result.LongestThread = &ThreadSummary{
+		PullRequest: toSimplePullRequest(longest.pullRequest),
+		Comments:    len(longest.comments),
+		Turns:       longest.turns(),
+		Participants: len(lo.Uniq(lo.Map(longest.comments, func(comment repository.PullRequestComment, _ int) string {
+			return comment.Author
+		}))),
+	}
+
+	return &result
+}
+
+// PR のレビューコメントを ReplyTo の先のコメントごとにスレッドにまとめる。コメントは取得した順に並べる
+// 返信先が取得できていないコメントはスレッドの始まりとみなす
+func newCommentThreads(pr *repository.PullRequest) []commentThread {
+	comments := lo.FlatMap(pr.Reviews, func(review repository.PullRequestReview, _ int) []repository.PullRequestComment {
+		return review.Comments
+	})
+	byID := lo.KeyBy(comments, func(comment repository.PullRequestComment) string {
+		return comment.ID
+	})
+
+	rootOf := func(comment repository.PullRequestComment) repository.PullRequestComment {
+		if root, ok := byID[comment.ReplyTo]; ok && comment.ReplyTo != "" {
+			return root
+		}
+		return comment
+	}
+
+	var threads []commentThread
+	indexByRoot := map[string]int{}
+	for _, comment := range comments {
+		root := rootOf(comment)
+		i, ok := indexByRoot[root.ID]
+		if !ok {
+			i = len(threads)
+			indexByRoot[root.ID] = i
+			threads = append(threads, commentThread{pullRequest: pr, root: root})
+		}
+
+		threads[i].comments = append(threads[i].comments, comment)
+	}
+
+	// スレッドの最初のコメントを先頭にそろえる
+	for i := range threads {
+		threads[i].comments = append(
+			[]repository.PullRequestComment{threads[i].root},
+			lo.Filter(threads[i].comments, func(comment repository.PullRequestComment, _ int) bool {
+				return comment.ID != threads[i].root.ID
+			})...,
+		)
+	}
+
+	return threads
+}
+
+// PR のレビューコメントのうち、返信の数
+func countReplies(pr *repository.PullRequest) int {
+	return lo.SumBy(pr.Reviews, func(review repository.PullRequestReview) int {
+		return lo.CountBy(review.Comments, func(comment repository.PullRequestComment) bool {
+			return comment.ReplyTo != ""
+		})
+	})
+}
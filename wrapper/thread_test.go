@@ -0,0 +1,39 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+func TestWrapThreads_turns(t *testing.T) {
+	comment := func(id, author, replyTo string) repository.PullRequestComment {
+		return repository.PullRequestComment{ID: id, Author: author, ReplyTo: replyTo}
+	}
+	pr := &repository.PullRequest{
+		URL: "https://github.com/owner/repo/pull/1",
+		Reviews: []repository.PullRequestReview{
+			{Comments: []repository.PullRequestComment{comment("a1", "alice", ""), comment("b1", "bob", "")}},
+			{Comments: []repository.PullRequestComment{comment("a2", "me", "a1"), comment("b2", "bob", "b1")}},
+			{Comments: []repository.PullRequestComment{comment("b3", "bob", "b1"), comment("a3", "alice", "a1")}},
+			{Comments: []repository.PullRequestComment{comment("a4", "me", "a1")}},
+		},
+	}
+
+	got := wrapThreads("me", []*repository.PullRequest{pr}, &config.Config{})
+
+	// alice → me → alice → me の 4 番と、bob が続けて書いた 1 番
+	if got.ThreadCount != 2 || got.DiscussedThreadCount != 2 {
+		t.Errorf("ThreadCount = %d, DiscussedThreadCount = %d, want 2, 2", got.ThreadCount, got.DiscussedThreadCount)
+	}
+	if got.AverageTurns != 2.5 || got.MaxTurns != 4 {
+		t.Errorf("AverageTurns = %v, MaxTurns = %d, want 2.5, 4", got.AverageTurns, got.MaxTurns)
+	}
+	if got.LongestThread == nil || got.LongestThread.Turns != 4 || got.LongestThread.Comments != 4 || got.LongestThread.Participants != 2 {
+		t.Errorf("LongestThread = %+v, want 4 turns, 4 comments and 2 participants", got.LongestThread)
+	}
+	if got.StartedCount != 0 || got.AnsweredCount != 1 {
+		t.Errorf("StartedCount = %d, AnsweredCount = %d, want 0, 1", got.StartedCount, got.AnsweredCount)
+	}
+}
@@ -0,0 +1,112 @@
+package wrapper
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// 要素を 1 件ずつ受け取り、上位 n 件だけをヒープで保持するランキング
+// 全件を並べ替えないので、m 件を受け取るのにかかる時間は O(m log n)、メモリは O(n)
+type topN[T any] struct {
+	n int
+	// a が b より上位か。同順位の並び順も含めた全順序にする
+	less func(a, b T) bool
+	// a と b が同順位か (less の同順位の並び順は無視する)
+	tied func(a, b T) bool
+	// 最も下位の要素が先頭にくるヒープ
+	items topNHeap[T]
+	// 受け取った件数。less で順序がつかない要素は先に受け取った方を上位にする
+	seq int
+	// 上位 n 件に入らなかった要素のうち、ヒープの先頭 (n 件目) と同順位のものの数
+	tiedBeyondCutoff int
+}
+
+type topNItem[T any] struct {
+	value T
+	seq   int
+}
+
+type topNHeap[T any] struct {
+	items []topNItem[T]
+	less  func(a, b topNItem[T]) bool
+}
+
+func (h topNHeap[T]) Len() int { return len(h.items) }
+
+// 下位のものほど先頭にくる
+func (h topNHeap[T]) Less(i, j int) bool { return h.less(h.items[j], h.items[i]) }
+
+func (h topNHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *topNHeap[T]) Push(x any) { h.items = append(h.items, x.(topNItem[T])) }
+
+func (h *topNHeap[T]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+func newTopN[T any](n int, less, tied func(a, b T) bool) *topN[T] {
+	if n < 1 {
+		panic("n must be greater than 0")
+	}
+
+	t := &topN[T]{n: n, less: less, tied: tied}
+	t.items = topNHeap[T]{
+		items: make([]topNItem[T], 0, n),
+		less: func(a, b topNItem[T]) bool {
+			if less(a.value, b.value) {
+				return true
+			}
+			if less(b.value, a.value) {
+				return false
+			}
+			return a.seq < b.seq
+		},
+	}
+
+	return t
+}
+
+func (t *topN[T]) add(value T) {
+	item := topNItem[T]{value: value, seq: t.seq}
+	t.seq++
+
+	if t.items.Len() < t.n {
+		heap.Push(&t.items, item)
+		return
+	}
+
+	cutoff := t.items.items[0]
+	if !t.items.less(item, cutoff) {
+		if t.tied(item.value, cutoff.value) {
+			t.tiedBeyondCutoff++
+		}
+		return
+	}
+
+	t.items.items[0] = item
+	heap.Fix(&t.items, 0)
+	// n 件目が同順位のまま入れ替わった場合は、押し出した要素も同順位として数える
+	// 順位が上がった場合は、それまでに数えた要素はすべて n 件目より下位になる
+	if t.tied(cutoff.value, t.items.items[0].value) {
+		t.tiedBeyondCutoff++
+	} else {
+		t.tiedBeyondCutoff = 0
+	}
+}
+
+// 上位のものから順に返す
+func (t *topN[T]) sorted() []T {
+	items := append([]topNItem[T]{}, t.items.items...)
+	sort.Slice(items, func(i, j int) bool {
+		return t.items.less(items[i], items[j])
+	})
+
+	values := make([]T, len(items))
+	for i, item := range items {
+		values[i] = item.value
+	}
+
+	return values
+}
@@ -0,0 +1,79 @@
+package wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopN(t *testing.T) {
+	type entry struct {
+		name  string
+		value int
+	}
+	newRanking := func() *topN[entry] {
+		return newTopN(
+			2,
+			func(a, b entry) bool { return a.value > b.value },
+			func(a, b entry) bool { return a.value == b.value },
+		)
+	}
+
+	tests := []struct {
+		name                 string
+		entries              []entry
+		want                 []string
+		wantTiedBeyondCutoff int
+	}{
+		{
+			name:    "higher entries replace the cutoff",
+			entries: []entry{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}},
+			want:    []string{"d", "c"},
+		},
+		{
+			name:                 "ties with the cutoff are counted",
+			entries:              []entry{{"a", 3}, {"b", 2}, {"c", 2}, {"d", 2}, {"e", 1}},
+			want:                 []string{"a", "b"},
+			wantTiedBeyondCutoff: 2,
+		},
+		{
+			name:    "the count is reset when the cutoff rises",
+			entries: []entry{{"a", 3}, {"b", 2}, {"c", 2}, {"d", 3}},
+			want:    []string{"a", "d"},
+		},
+		{
+			name:                 "the pushed out entry is counted when the cutoff stays tied",
+			entries:              []entry{{"a", 2}, {"b", 2}, {"c", 3}},
+			want:                 []string{"c", "a"},
+			wantTiedBeyondCutoff: 1,
+		},
+		{
+			name:    "earlier entries win ties",
+			entries: []entry{{"b", 1}, {"a", 1}},
+			want:    []string{"b", "a"},
+		},
+		{
+			name:    "fewer entries than n",
+			entries: []entry{{"a", 1}},
+			want:    []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranking := newRanking()
+			for _, e := range tt.entries {
+				ranking.add(e)
+			}
+
+			got := make([]string, 0, len(tt.want))
+			for _, e := range ranking.sorted() {
+				got = append(got, e.name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted() = %v, want %v", got, tt.want)
+			}
+			if ranking.tiedBeyondCutoff != tt.wantTiedBeyondCutoff {
+				t.Errorf("tiedBeyondCutoff = %d, want %d", ranking.tiedBeyondCutoff, tt.wantTiedBeyondCutoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package wrapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+)
+
+// 作成した時期ごとの PR の推移
+type PullRequestTrends struct {
+	// 1 月 ~ 12 月
+	Monthly []PullRequestTrendPoint
+	// 1 月 1 日を含む ISO 週から 12 月 31 日を含む ISO 週まで
+	Weekly []PullRequestTrendPoint
+}
+
+type PullRequestTrendPoint struct {
+	// 2006-01 形式の月、または 2006-W01 形式の ISO 週
+	Period string
+	// この期間に作成した PR の数
+	OpenedCount int
+	// OpenedCount のうちマージされた PR の数
+	MergedCount int
+	// OpenedCount のうちマージされた割合 (0 ~ 1)
+	MergeRate float64
+	// マージされた PR のマージまでの時間の中央値
+	MedianLifetime time.Duration
+	// 営業時間だけで数えた MedianLifetime。business_hours を設定していない場合は nil
+	MedianBusinessLifetime *time.Duration
+}
+
+func init() {
+	RegisterPullRequestMetric(NewStreamingPullRequestMetric(
+		"trends",
+		[]MetricData{MetricDataPullRequests, MetricDataTimeline},
+		func(dataset *PullRequestDataset) MetricAccumulator {
+			return &trendsAccumulator{
+				cfg:     dataset.Config,
+				monthly: map[string]*trendPeriod{},
+				weekly:  map[string]*trendPeriod{},
+			}
+		},
+	))
+}
+
+type trendsAccumulator struct {
+	cfg *config.Config
+	// 2006-01 形式の月、2006-W01 形式の ISO 週ごとの集計
+	monthly map[string]*trendPeriod
+	weekly  map[string]*trendPeriod
+}
+
+type trendPeriod struct {
+	openedCount int
+	// マージされた PR のマージまでの時間
+	lifetimes *spanSketch
+}
+
+func (a *trendsAccumulator) monthOf(t time.Time) string {
+	return t.In(a.cfg.Location()).Format("2006-01")
+}
+
+func (a *trendsAccumulator) weekOf(t time.Time) string {
+	year, week := t.In(a.cfg.Location()).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+func (a *trendsAccumulator) Add(pr *repository.PullRequest) {
+	for _, period := range []*trendPeriod{
+		a.periodOf(a.monthly, a.monthOf(pr.CreatedAt)),
+		a.periodOf(a.weekly, a.weekOf(pr.CreatedAt)),
+	} {
+		period.openedCount++
+		if isPullRequestMerged(pr) {
+			period.lifetimes.add(pullRequestLifetime(pr, a.cfg.LifetimeFrom))
+		}
+	}
+}
+
+func (a *trendsAccumulator) periodOf(periods map[string]*trendPeriod, period string) *trendPeriod {
+	if periods[period] == nil {
+		periods[period] = &trendPeriod{lifetimes: newSpanSketch(a.cfg.BusinessHours)}
+	}
+
+	return periods[period]
+}
+
+func (a *trendsAccumulator) Result() any {
+	var months, weeks []string
+	for month := a.cfg.From(); month.Before(a.cfg.To()); month = month.AddDate(0, 1, 0) {
+		months = append(months, a.monthOf(month))
+	}
+	for day := a.cfg.From(); !day.After(a.cfg.To()); day = day.AddDate(0, 0, 1) {
+		if week := a.weekOf(day); len(weeks) == 0 || weeks[len(weeks)-1] != week {
+			weeks = append(weeks, week)
+		}
+	}
+
+	return PullRequestTrends{
+		Monthly: a.trendPoints(a.monthly, months),
+		Weekly:  a.trendPoints(a.weekly, weeks),
+	}
+}
+
+// periods の順に集計結果を並べる。PR のない期間は 0 件とする
+func (a *trendsAccumulator) trendPoints(byPeriod map[string]*trendPeriod, periods []string) []PullRequestTrendPoint {
+	return lo.Map(periods, func(period string, _ int) PullRequestTrendPoint {
+		p := byPeriod[period]
+		if p == nil {
+			p = &trendPeriod{lifetimes: newSpanSketch(a.cfg.BusinessHours)}
+		}
+
+		lifetimeStats := p.lifetimes.stats()
+		point := PullRequestTrendPoint{
+			Period:         period,
+			OpenedCount:    p.openedCount,
+			MergedCount:    p.lifetimes.durations.count,
+			MedianLifetime: lifetimeStats.Percentile50,
+		}
+		if lifetimeStats.BusinessHours != nil {
+			point.MedianBusinessLifetime = lo.ToPtr(lifetimeStats.BusinessHours.Percentile50)
+		}
+		if point.OpenedCount > 0 {
+			point.MergeRate = float64(point.MergedCount) / float64(point.OpenedCount)
+		}
+
+		return point
+	})
+}
@@ -0,0 +1,69 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/businesshours"
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+	"github.com/samber/lo"
+	"github.com/volatiletech/null/v8"
+)
+
+func TestTrendsAccumulator_businessHours(t *testing.T) {
+	// 3 月 3 日 (金) 17:00 に作成し、週末をはさんで 3 月 6 日 (月) 10:00 にマージ
+	createdAt := time.Date(2023, time.March, 3, 17, 0, 0, 0, time.UTC)
+	pr := &repository.PullRequest{
+		State:     repository.PullRequestStateMerged,
+		CreatedAt: createdAt,
+		MergedAt:  null.TimeFrom(time.Date(2023, time.March, 6, 10, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name     string
+		calendar *businesshours.Calendar
+		// 3 月と PR のない 1 月の MedianBusinessLifetime。nil の場合は nil であることを確かめる
+		wantMarch, wantJanuary *time.Duration
+	}{
+		{
+			name:        "with business hours",
+			calendar:    businesshours.Default(time.UTC),
+			wantMarch:   lo.ToPtr(2 * time.Hour),
+			wantJanuary: lo.ToPtr(time.Duration(0)),
+		},
+		{
+			name: "without business hours",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&config.Config{LifetimeFrom: config.LifetimeFromCreated, BusinessHours: tt.calendar}).ForYear(2023)
+			accumulator := &trendsAccumulator{cfg: cfg, monthly: map[string]*trendPeriod{}, weekly: map[string]*trendPeriod{}}
+			accumulator.Add(pr)
+
+			trends := accumulator.Result().(PullRequestTrends)
+			march := trends.Monthly[2]
+			if march.MergedCount != 1 || march.MedianLifetime != 65*time.Hour {
+				t.Errorf("March = %+v, want 1 merged in 65h", march)
+			}
+			for _, c := range []struct {
+				point PullRequestTrendPoint
+				want  *time.Duration
+			}{
+				{march, tt.wantMarch},
+				{trends.Monthly[0], tt.wantJanuary},
+			} {
+				got := c.point.MedianBusinessLifetime
+				if (got == nil) != (c.want == nil) || (got != nil && *got != *c.want) {
+					t.Errorf("%s MedianBusinessLifetime = %v, want %v", c.point.Period, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
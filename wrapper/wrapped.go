@@ -0,0 +1,127 @@
+package wrapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmtym1998/gh-wrapped/config"
+	"github.com/kmtym1998/gh-wrapped/repository"
+)
+
+// すべてのセクションをまとめた集計結果。集計しなかったセクションは nil
+type WrappedResult struct {
+	Login         string
+	Year          int
+	PullRequests  *WrappedResultPullRequest
+	Stale         *WrappedResultStalePullRequests
+	ReviewRounds  *WrappedResultReviewRounds
+	Threads       *WrappedResultThreads
+	Reviews       *WrappedResultReviews
+	Rhythm        *WrappedResultRhythm
+	Collaboration *WrappedResultCollaboration
+	Bots          *WrappedResultBots
+	Issues        *WrappedResultIssues
+	Commits       *WrappedResultCommits
+	Calendar      *WrappedResultCalendar
+	Achievements  *WrappedResultAchievements
+}
+
+// すべてのセクションを集計する
+// ユーザー・PR・レビューは 1 回だけ取得し、それを使うセクションで使い回す
+func Wrap(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResult, error) {
+	// 設定ファイルの実績の誤りは API を呼ぶ前に返す
+	achievementRules, err := newAchievementRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := fetchPullRequestSource(repo, cfg, repository.AllPullRequestFields())
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	result, err := wrapPullRequestSections(repo, source, reviews, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Reviews = wrapReviews(source.login, reviews, cfg)
+	result.Rhythm = wrapRhythm(source.login, source.pullRequests, reviews, cfg)
+
+	result.Collaboration, err = wrapCollaboration(repo, source, reviews, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap collaboration: %w", err)
+	}
+
+	issues, err := repo.ListIssues(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	result.Issues = wrapIssues(source.login, issues, cfg)
+
+	contributions, err := repo.ListCommitContributions(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit contributions: %w", err)
+	}
+	result.Commits = wrapCommits(source.login, contributions, source.all, cfg)
+
+	calendar, err := repo.GetContributionCalendar(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contribution calendar: %w", err)
+	}
+	result.Calendar = wrapCalendar(source.login, calendar, time.Now().In(cfg.Location()))
+
+	result.Achievements, err = evaluateAchievements(result, achievementRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// 作成した PR に関するセクション (PR・放置された PR・レビューの往復・スレッド・bot の活動) を集計する
+func WrapPullRequestSections(repo repository.GitHubRepository, cfg *config.Config) (*WrappedResult, error) {
+	source, err := fetchPullRequestSource(repo, cfg, repository.AllPullRequestFields())
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := repo.ListGivenReviews(cfg.From(), cfg.To())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	return wrapPullRequestSections(repo, source, reviews, cfg)
+}
+
+func wrapPullRequestSections(
+	repo repository.GitHubRepository,
+	source *pullRequestSource,
+	reviews []*repository.GivenReview,
+	cfg *config.Config,
+) (*WrappedResult, error) {
+	pr, err := wrapPullRequest(repo, source, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap pull requests: %w", err)
+	}
+
+	openPullRequests, err := listFilteredOpenPullRequests(repo, cfg, openPullRequestFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WrappedResult{
+		Login:        source.login,
+		Year:         cfg.Year(),
+		PullRequests: pr,
+		Stale:        wrapStalePullRequests(source.login, source.pullRequests, openPullRequests, cfg, time.Now()),
+		ReviewRounds: wrapReviewRounds(source.login, source.pullRequests, cfg),
+		Threads:      wrapThreads(source.login, source.pullRequests, cfg),
+		Bots:         wrapBots(source.login, source.pullRequestsWithBots, reviews, cfg),
+	}, nil
+}